@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// File is the content of the optional config file given with "router_config_file"
+type File struct {
+	Routes      []Route              `yaml:"routes"`
+	Rewrites    []Rewrite            `yaml:"rewrites"`
+	Ratelimit   RatelimitPolicies    `yaml:"ratelimit"`
+	Concurrency []ServiceConcurrency `yaml:"concurrency"`
+	Access      Access               `yaml:"access"`
+}
+
+// Access are client networks allowed or denied for all routes, next to the router_allow_cidrs/router_deny_cidrs flags
+type Access struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+// ServiceConcurrency limits the concurrent requests to all routes of Service
+type ServiceConcurrency struct {
+	Service      string        `yaml:"service"`
+	MaxInFlight  int           `yaml:"maxInFlight"`
+	QueueSize    int           `yaml:"queueSize"`
+	QueueTimeout time.Duration `yaml:"queueTimeout"`
+}
+
+// RatelimitPolicies are gateway side rate limits, defaults apply to routes without limits, ceilings to all routes
+type RatelimitPolicies struct {
+	Defaults []RatelimitPolicy `yaml:"defaults"`
+	Ceilings []RatelimitPolicy `yaml:"ceilings"`
+}
+
+// RatelimitPolicy matches the routes of Service and/or the routes whose path matches Path,
+// a Path ending with "*" matches all paths with that prefix, the most specific policies win
+type RatelimitPolicy struct {
+	Service string   `yaml:"service"`
+	Path    string   `yaml:"path"`
+	Key     string   `yaml:"key"`
+	Rates   []string `yaml:"rates"`
+	Group   string   `yaml:"group"`
+}
+
+// Rewrite rewrites the path of requests matching Match, Match is a regexp when it starts with "^" else a gin like template
+type Rewrite struct {
+	Method  string `yaml:"method"`
+	Match   string `yaml:"match"`
+	Replace string `yaml:"replace"`
+}
+
+// Ratelimit is a router.Ratelimit
+type Ratelimit struct {
+	Key   string   `yaml:"key"`
+	Rates []string `yaml:"rates"`
+	Group string   `yaml:"group"`
+}
+
+// Param is a router.Param
+type Param struct {
+	Name     string `yaml:"name"`
+	Type     string `yaml:"type"`
+	Location string `yaml:"location"`
+	Required bool   `yaml:"required"`
+	Default  string `yaml:"default"`
+	Items    string `yaml:"items"`
+}
+
+// Route is a static route, it has the same fields as router.Route plus the service it targets
+type Route struct {
+	Service              string        `yaml:"service"`
+	RouterURI            string        `yaml:"routerURI"`
+	IsGlobal             bool          `yaml:"isGlobal"` // isGlobal=True == no prefix route
+	Type                 string        `yaml:"type"`
+	Method               string        `yaml:"method"`
+	Path                 string        `yaml:"path"`
+	Endpoint             string        `yaml:"endpoint"`
+	Params               []string      `yaml:"params"`
+	TypedParams          []Param       `yaml:"typedParams"`
+	ArrayStyle           string        `yaml:"arrayStyle"`
+	AuthRequired         bool          `yaml:"authRequired"`
+	RatelimitClientIP    []string      `yaml:"ratelimitClientIP"`
+	RatelimitUser        []string      `yaml:"ratelimitUser"`
+	Ratelimits           []Ratelimit   `yaml:"ratelimits"`
+	Upstream             string        `yaml:"upstream"`
+	UpstreamPath         string        `yaml:"upstreamPath"`
+	GRPCWeb              bool          `yaml:"grpcWeb"`
+	Rewrites             []Rewrite     `yaml:"rewrites"`
+	Redirect             string        `yaml:"redirect"`
+	RedirectCode         int           `yaml:"redirectCode"`
+	MaxInFlight          int           `yaml:"maxInFlight"`
+	QueueSize            int           `yaml:"queueSize"`
+	QueueTimeout         time.Duration `yaml:"queueTimeout"`
+	AllowCIDRs           []string      `yaml:"allowCIDRs"`
+	DenyCIDRs            []string      `yaml:"denyCIDRs"`
+	MaxBodySize          int64         `yaml:"maxBodySize"`
+	MaxMultipartParts    int           `yaml:"maxMultipartParts"`
+	MaxMultipartPartSize int64         `yaml:"maxMultipartPartSize"`
+	MaxJSONDepth         int           `yaml:"maxJSONDepth"`
+	Schema               string        `yaml:"schema"`
+	Audit                bool          `yaml:"audit"`
+	ForwardCredentials   bool          `yaml:"forwardCredentials"`
+}
+
+// LoadFile reads and validates a YAML or JSON config file
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is valid YAML, so the YAML parser handles both
+	result := &File{}
+	if err := yaml.UnmarshalStrict(data, result); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for idx, r := range result.Routes {
+		if r.Service == "" {
+			return nil, fmt.Errorf("%s: route %d has no service", path, idx)
+		}
+		switch r.Type {
+		case "", "rpc":
+			if r.Endpoint == "" {
+				return nil, fmt.Errorf("%s: route %d has no endpoint", path, idx)
+			}
+		case "http", "maintenance":
+		case "redirect":
+			if r.Redirect == "" {
+				return nil, fmt.Errorf("%s: route %d has no redirect", path, idx)
+			}
+			switch r.RedirectCode {
+			case 0, 301, 302, 303, 307, 308:
+			default:
+				return nil, fmt.Errorf("%s: route %d has an invalid redirectCode %d", path, idx, r.RedirectCode)
+			}
+		default:
+			return nil, fmt.Errorf("%s: route %d has an unknown type '%s'", path, idx, r.Type)
+		}
+		if r.Method == "" {
+			result.Routes[idx].Method = "GET"
+		}
+		if r.Path == "" {
+			result.Routes[idx].Path = "/"
+		}
+	}
+
+	for idx, c := range result.Concurrency {
+		if c.Service == "" || c.MaxInFlight < 1 {
+			return nil, fmt.Errorf("%s: concurrency %d needs a service and maxInFlight", path, idx)
+		}
+	}
+
+	for idx, r := range result.Rewrites {
+		if r.Match == "" || r.Replace == "" {
+			return nil, fmt.Errorf("%s: rewrite %d needs match and replace", path, idx)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+		check   func(t *testing.T, f *File)
+	}{
+		{
+			name: "yaml with defaults",
+			content: `
+routes:
+  - service: jo.micro.users
+    endpoint: UserService.List
+concurrency:
+  - service: jo.micro.users
+    maxInFlight: 5
+    queueTimeout: 2s
+`,
+			check: func(t *testing.T, f *File) {
+				r := f.Routes[0]
+				if r.Method != "GET" || r.Path != "/" {
+					t.Errorf("got method %s path %s, want GET /", r.Method, r.Path)
+				}
+				if f.Concurrency[0].QueueTimeout != 2*time.Second {
+					t.Errorf("got queueTimeout %s, want 2s", f.Concurrency[0].QueueTimeout)
+				}
+			},
+		},
+		{
+			name:    "json",
+			content: `{"routes": [{"service": "legacy", "type": "redirect", "path": "/old", "redirect": "/new", "redirectCode": 301}]}`,
+			check: func(t *testing.T, f *File) {
+				if r := f.Routes[0]; r.Redirect != "/new" || r.RedirectCode != 301 {
+					t.Errorf("got redirect %s %d, want /new 301", r.Redirect, r.RedirectCode)
+				}
+			},
+		},
+		{name: "unknown field", content: "routes:\n  - service: a\n    endpoint: E\n    bogus: true\n", wantErr: "bogus"},
+		{name: "no service", content: "routes:\n  - endpoint: E\n", wantErr: "has no service"},
+		{name: "rpc without endpoint", content: "routes:\n  - service: a\n", wantErr: "has no endpoint"},
+		{name: "redirect without target", content: "routes:\n  - service: a\n    type: redirect\n", wantErr: "has no redirect"},
+		{name: "invalid redirect code", content: "routes:\n  - service: a\n    type: redirect\n    redirect: /b\n    redirectCode: 200\n", wantErr: "invalid redirectCode"},
+		{name: "unknown type", content: "routes:\n  - service: a\n    type: ftp\n", wantErr: "unknown type"},
+		{name: "concurrency without maxInFlight", content: "concurrency:\n  - service: a\n", wantErr: "needs a service and maxInFlight"},
+		{name: "rewrite without replace", content: "rewrites:\n  - match: /a\n", wantErr: "needs match and replace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "router.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := LoadFile(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, f)
+		})
+	}
+}
+
+func TestLoadFileMissing(t *testing.T) {
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml")); !os.IsNotExist(err) {
+		t.Errorf("got error %v, want not exist", err)
+	}
+}
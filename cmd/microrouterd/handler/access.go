@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"jochum.dev/jo-micro/router/cmd/microrouterd/config"
+)
+
+// ipAccess are networks allowed or denied, deny wins and an empty allow list allows all
+type ipAccess struct {
+	allow []*net.IPNet
+	deny  []*net.IPNet
+}
+
+// parseCIDRs parses networks, plain IPs are networks with a single address
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	result := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP '%s'", cidr)
+			}
+			if ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, network)
+	}
+
+	return result, nil
+}
+
+func newIPAccess(allow, deny []string) (*ipAccess, error) {
+	if len(allow) == 0 && len(deny) == 0 {
+		return nil, nil
+	}
+
+	var (
+		result = &ipAccess{}
+		err    error
+	)
+	if result.allow, err = parseCIDRs(allow); err != nil {
+		return nil, err
+	}
+	if result.deny, err = parseCIDRs(deny); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// newGlobalIPAccess builds the lists for all routes from the flags and the config file
+func newGlobalIPAccess(allow, deny []string, cfg *config.File) (*ipAccess, error) {
+	if cfg != nil {
+		allow = append(append([]string{}, allow...), cfg.Access.Allow...)
+		deny = append(append([]string{}, deny...), cfg.Access.Deny...)
+	}
+
+	return newIPAccess(allow, deny)
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, n := range networks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowed returns true if ip may pass, a nil ipAccess allows all
+func (a *ipAccess) allowed(ip net.IP) bool {
+	if a == nil {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+
+	if containsIP(a.deny, ip) {
+		return false
+	}
+
+	return len(a.allow) == 0 || containsIP(a.allow, ip)
+}
+
+// checkAccess aborts with 403 if the client IP is denied globally or by the route
+func (h *Handler) checkAccess(c *gin.Context, pr *proxyRoute) bool {
+	ip := net.ParseIP(c.ClientIP())
+	access, _ := h.access.Load().(*ipAccess)
+	if access.allowed(ip) && pr.access.allowed(ip) {
+		return true
+	}
+
+	abortWithError(c, http.StatusForbidden, "FORBIDDEN", "access denied")
+	return false
+}
+
+// ForwardedMiddleware turns a RFC 7239 Forwarded header into X-Forwarded-For so gin's ClientIP uses it.
+// It's only used when the request comes straight from one of the trusted proxies and has no X-Forwarded-For,
+// what a proxy added to X-Forwarded-For always wins over a Forwarded header the client sent along
+func ForwardedMiddleware(trustedProxies []string) (gin.HandlerFunc, error) {
+	trusted, err := parseCIDRs(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *gin.Context) {
+		forwarded := strings.Join(c.Request.Header.Values("Forwarded"), ",")
+		if forwarded == "" || c.Request.Header.Get("X-Forwarded-For") != "" {
+			c.Next()
+			return
+		}
+
+		host, _, err := net.SplitHostPort(strings.TrimSpace(c.Request.RemoteAddr))
+		if err != nil || !containsIP(trusted, net.ParseIP(host)) {
+			c.Next()
+			return
+		}
+
+		ips := []string{}
+		for _, element := range strings.Split(forwarded, ",") {
+			for _, pair := range strings.Split(element, ";") {
+				key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if !ok || !strings.EqualFold(key, "for") {
+					continue
+				}
+
+				if ip := forwardedIP(value); ip != "" {
+					ips = append(ips, ip)
+				}
+			}
+		}
+
+		if len(ips) > 0 {
+			c.Request.Header.Set("X-Forwarded-For", strings.Join(ips, ", "))
+		}
+		c.Next()
+	}, nil
+}
+
+// forwardedIP returns the IP of a Forwarded "for" value like 192.0.2.60, "192.0.2.60:4711" or "[2001:db8:cafe::17]:4711"
+func forwardedIP(value string) string {
+	value = strings.Trim(value, `"`)
+
+	if strings.HasPrefix(value, "[") {
+		end := strings.Index(value, "]")
+		if end < 0 {
+			return ""
+		}
+		value = value[1:end]
+	} else if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+
+	// Obfuscated identifiers like "unknown" or "_hidden" aren't IPs
+	if net.ParseIP(value) == nil {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestForwardedMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		xff        string
+		want       string
+	}{
+		{name: "no headers", remoteAddr: "10.0.0.2:1234", want: "10.0.0.2"},
+		{name: "only X-Forwarded-For", remoteAddr: "10.0.0.2:1234", xff: "192.0.2.1", want: "192.0.2.1"},
+		{name: "Forwarded", remoteAddr: "10.0.0.2:1234", forwarded: []string{"for=192.0.2.60;proto=http, for=198.51.100.17"}, want: "198.51.100.17"},
+		{name: "Forwarded through trusted proxies", remoteAddr: "10.0.0.2:1234", forwarded: []string{"for=192.0.2.60", "for=10.0.0.5"}, want: "192.0.2.60"},
+		{name: "IPv6", remoteAddr: "10.0.0.2:1234", forwarded: []string{`for="[2001:db8:cafe::17]:4711"`}, want: "2001:db8:cafe::17"},
+		{name: "X-Forwarded-For of the proxy wins", remoteAddr: "10.0.0.2:1234", forwarded: []string{"for=1.2.3.4"}, xff: "203.0.113.9", want: "203.0.113.9"},
+		{name: "Forwarded without IPs keeps X-Forwarded-For", remoteAddr: "10.0.0.2:1234", forwarded: []string{"for=unknown"}, xff: "203.0.113.9", want: "203.0.113.9"},
+		{name: "Forwarded without IPs", remoteAddr: "10.0.0.2:1234", forwarded: []string{"for=unknown"}, want: "10.0.0.2"},
+		{name: "untrusted client", remoteAddr: "198.51.100.7:1234", forwarded: []string{"for=1.2.3.4"}, want: "198.51.100.7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forwarded, err := ForwardedMiddleware([]string{"10.0.0.0/8"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got string
+			r := gin.New()
+			r.ForwardedByClientIP = true
+			if err := r.SetTrustedProxies([]string{"10.0.0.0/8"}); err != nil {
+				t.Fatal(err)
+			}
+			r.Use(forwarded)
+			r.GET("/", func(c *gin.Context) {
+				got = c.ClientIP()
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, f := range tt.forwarded {
+				req.Header.Add("Forwarded", f)
+			}
+			if tt.xff != "" {
+				req.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("got client IP %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForwardedIP(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"192.0.2.60", "192.0.2.60"},
+		{`"192.0.2.60:4711"`, "192.0.2.60"},
+		{`"[2001:db8:cafe::17]:4711"`, "2001:db8:cafe::17"},
+		{`"[2001:db8:cafe::17]"`, "2001:db8:cafe::17"},
+		{`"[2001:db8:cafe::17"`, ""},
+		{"unknown", ""},
+		{"_hidden", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := forwardedIP(tt.value); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
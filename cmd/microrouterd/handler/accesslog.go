@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+	"go-micro.dev/v4/broker"
+	"jochum.dev/jo-micro/auth2"
+)
+
+const (
+	accessLogSinkStdout = "stdout"
+	accessLogSinkFile   = "file"
+	accessLogSinkBroker = "broker"
+	accessLogSinkOff    = "off"
+)
+
+const (
+	proxyRouteContextKey = "router.proxyRoute"
+	userContextKey       = "router.user"
+)
+
+// accessLogFields are all fields of an access log entry
+var accessLogFields = []string{
+	"time", "method", "path", "query", "route", "service", "endpoint", "status", "latency_ms",
+	"bytes", "client_ip", "user_agent", "user_id", "ratelimit", "error",
+}
+
+// AccessLog writes a JSON line per request with what the gateway did with it
+type AccessLog struct {
+	sink      string
+	fields    []string
+	redact    map[string]bool
+	sample    float64
+	writerMu  sync.Mutex
+	writer    io.Writer
+	file      *rotatingFile
+	publisher *asyncPublisher
+}
+
+// NewAccessLog creates the access log from the router_accesslog flags, b is needed for the broker sink
+func NewAccessLog(c *cli.Context, b broker.Broker, logger *logrus.Logger) (*AccessLog, error) {
+	a := &AccessLog{
+		sink:   c.String("router_accesslog"),
+		fields: accessLogFields,
+		redact: make(map[string]bool),
+		sample: c.Float64("router_accesslog_sample"),
+	}
+
+	if fields := c.StringSlice("router_accesslog_fields"); len(fields) > 0 {
+		for _, f := range fields {
+			if !validAccessLogField(f) {
+				return nil, fmt.Errorf("unknown access log field '%s'", f)
+			}
+		}
+		a.fields = fields
+	}
+	for _, f := range c.StringSlice("router_accesslog_redact") {
+		if !validAccessLogField(f) {
+			return nil, fmt.Errorf("unknown access log field '%s'", f)
+		}
+		a.redact[f] = true
+	}
+	if a.sample < 0 || a.sample > 1 {
+		return nil, fmt.Errorf("router_accesslog_sample must be between 0 and 1")
+	}
+
+	switch a.sink {
+	case accessLogSinkStdout:
+		a.writer = os.Stdout
+	case accessLogSinkFile:
+		file, err := newRotatingFile(c.String("router_accesslog_file"), c.Int64("router_accesslog_file_max_size")<<20, c.Int("router_accesslog_file_max_backups"))
+		if err != nil {
+			return nil, err
+		}
+		a.file = file
+		a.writer = file
+	case accessLogSinkBroker:
+		a.publisher = newAsyncPublisher(b, c.String("router_accesslog_topic"), c.Int("router_accesslog_buffer"), logger)
+	case accessLogSinkOff:
+	default:
+		return nil, fmt.Errorf("unknown access log sink '%s'", a.sink)
+	}
+
+	return a, nil
+}
+
+func validAccessLogField(field string) bool {
+	for _, f := range accessLogFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware logs the requests that pass it, server errors are logged regardless of sampling
+func (a *AccessLog) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if a.sink == accessLogSinkOff {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < 500 && a.sample < 1 && rand.Float64() >= a.sample {
+			return
+		}
+
+		a.write(a.entry(c, start, time.Since(start)))
+	}
+}
+
+func (a *AccessLog) entry(c *gin.Context, start time.Time, latency time.Duration) map[string]interface{} {
+	all := map[string]interface{}{
+		"time":       start.UTC().Format(time.RFC3339Nano),
+		"method":     c.Request.Method,
+		"path":       c.Request.URL.Path,
+		"query":      c.Request.URL.RawQuery,
+		"route":      c.FullPath(),
+		"status":     c.Writer.Status(),
+		"latency_ms": float64(latency) / float64(time.Millisecond),
+		"bytes":      c.Writer.Size(),
+		"client_ip":  c.ClientIP(),
+		"user_agent": c.Request.UserAgent(),
+	}
+
+	if v, ok := c.Get(proxyRouteContextKey); ok {
+		pr := v.(*proxyRoute)
+		all["service"] = pr.service
+		all["endpoint"] = pr.route.Endpoint
+	}
+	if v, ok := c.Get(userContextKey); ok {
+		all["user_id"] = v.(*auth2.User).Id
+	}
+	if v, ok := c.Get(rateLimitStateKey); ok && v.(*rateLimitState).outcome != "" {
+		all["ratelimit"] = v.(*rateLimitState).outcome
+	}
+	if len(c.Errors) > 0 {
+		all["error"] = c.Errors.String()
+	}
+
+	entry := make(map[string]interface{}, len(a.fields))
+	for _, f := range a.fields {
+		v, ok := all[f]
+		if !ok || v == "" {
+			continue
+		}
+		if a.redact[f] {
+			v = "REDACTED"
+		}
+		entry[f] = v
+	}
+
+	return entry
+}
+
+func (a *AccessLog) write(entry map[string]interface{}) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	if a.publisher != nil {
+		a.publisher.Publish(data)
+		return
+	}
+
+	a.writerMu.Lock()
+	defer a.writerMu.Unlock()
+	_, _ = a.writer.Write(append(data, '\n'))
+}
+
+// Close flushes the broker buffer and closes the file
+func (a *AccessLog) Close() error {
+	if a.publisher != nil {
+		return a.publisher.Close()
+	}
+	if a.file != nil {
+		return a.file.Close()
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"go-micro.dev/v4/errors"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gopkg.in/yaml.v2"
+	"jochum.dev/jo-micro/router/cmd/microrouterd/config"
+	"jochum.dev/jo-micro/router/internal/proto/routerserverpb"
+)
+
+// routeDisabled reports whether an admin disabled the route
+func (h *Handler) routeDisabled(pathMethod string) bool {
+	h.disabledMu.RLock()
+	defer h.disabledMu.RUnlock()
+
+	return h.disabled[pathMethod]
+}
+
+// secretFlagWords mark flags whose values effectiveFlags never shows
+var secretFlagWords = map[string]bool{"password": true, "passwd": true, "secret": true, "token": true, "key": true, "credentials": true}
+
+// secretFlag reports whether a word of the flags name marks it as secret
+func secretFlag(name string) bool {
+	for _, w := range strings.Split(strings.ToLower(name), "_") {
+		if secretFlagWords[w] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactURL hides the password of a URL, other values are returned as they are
+func redactURL(v string) string {
+	if u, err := url.Parse(v); err == nil && u.User != nil {
+		return u.Redacted()
+	}
+
+	return v
+}
+
+// redactedConfig returns a copy of the config file with the passwords in upstream URLs redacted
+func redactedConfig(f *config.File) *config.File {
+	if f == nil {
+		return nil
+	}
+
+	redacted := *f
+	redacted.Routes = make([]config.Route, len(f.Routes))
+	for idx, r := range f.Routes {
+		r.Upstream = redactURL(r.Upstream)
+		redacted.Routes[idx] = r
+	}
+
+	return &redacted
+}
+
+// effectiveFlags returns the values of all router_* flags, secret flags and passwords in URLs are redacted
+func effectiveFlags(c *cli.Context) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, f := range c.App.Flags {
+		name := f.Names()[0]
+		if !strings.HasPrefix(name, "router_") {
+			continue
+		}
+
+		if secretFlag(name) {
+			result[name] = "REDACTED"
+			continue
+		}
+
+		switch f.(type) {
+		case *cli.StringFlag:
+			result[name] = redactURL(c.String(name))
+		case *cli.StringSliceFlag:
+			values := c.StringSlice(name)
+			redacted := make([]string, len(values))
+			for idx, v := range values {
+				redacted[idx] = redactURL(v)
+			}
+			result[name] = redacted
+		case *cli.BoolFlag:
+			result[name] = c.Bool(name)
+		case *cli.IntFlag:
+			result[name] = c.Int(name)
+		case *cli.Int64Flag:
+			result[name] = c.Int64(name)
+		case *cli.Float64Flag:
+			result[name] = c.Float64(name)
+		case *cli.DurationFlag:
+			result[name] = c.Duration(name).String()
+		}
+	}
+
+	return result
+}
+
+// Services lists the services and the routes they own
+func (h *Handler) Services(ctx context.Context, in *emptypb.Empty, out *routerserverpb.ServicesReply) error {
+	services := make(map[string]*routerserverpb.ServicesReply_Service)
+	for pathMethod, pr := range h.loadTable().routes {
+		s, ok := services[pr.service]
+		if !ok {
+			s = &routerserverpb.ServicesReply_Service{Name: pr.service}
+			services[pr.service] = s
+		}
+
+		method, path, _ := strings.Cut(pathMethod, ":")
+		s.Routes = append(s.Routes, &routerserverpb.ServicesReply_Route{
+			Method:   method,
+			Path:     path,
+			Endpoint: pr.route.Endpoint,
+			Static:   pr.static,
+			Disabled: h.routeDisabled(pathMethod),
+		})
+	}
+
+	for _, s := range services {
+		sort.Slice(s.Routes, func(i, j int) bool {
+			if s.Routes[i].Path == s.Routes[j].Path {
+				return s.Routes[i].Method < s.Routes[j].Method
+			}
+			return s.Routes[i].Path < s.Routes[j].Path
+		})
+		out.Services = append(out.Services, s)
+	}
+	sort.Slice(out.Services, func(i, j int) bool { return out.Services[i].Name < out.Services[j].Name })
+
+	return nil
+}
+
+// Refresh asks all services for their routes now instead of waiting for the next refresh
+func (h *Handler) Refresh(ctx context.Context, in *emptypb.Empty, out *emptypb.Empty) error {
+	select {
+	case h.refreshNow <- struct{}{}:
+	default:
+		// A refresh is pending already
+	}
+
+	return nil
+}
+
+// RouteState returns the concurrency and, if a key is given, the ratelimit state of a route
+func (h *Handler) RouteState(ctx context.Context, in *routerserverpb.RouteStateRequest, out *routerserverpb.RouteStateReply) error {
+	pathMethod := fmt.Sprintf("%s:%s", in.Method, in.Path)
+	pr, ok := h.loadTable().routes[pathMethod]
+	if !ok {
+		return errors.NotFound(config.Name, "route not found")
+	}
+
+	out.Method = in.Method
+	out.Path = in.Path
+	out.Service = pr.service
+	out.Disabled = h.routeDisabled(pathMethod)
+	out.Instance = h.instanceID
+
+	if b := h.breakerFor(pr.service); b != nil {
+		state, failures, retryAt := b.snapshot()
+		out.Breaker = &routerserverpb.RouteStateReply_Breaker{State: state, Failures: int32(failures)}
+		if !retryAt.IsZero() {
+			out.Breaker.RetryAt = retryAt.Unix()
+		}
+	}
+
+	limits, _ := h.serviceConcurrency.Load().(map[string]*concurrencyLimit)
+	for _, l := range []struct {
+		scope string
+		limit *concurrencyLimit
+	}{{"route", pr.concurrency}, {"service", limits[pr.service]}} {
+		if l.limit == nil {
+			continue
+		}
+		out.Concurrency = append(out.Concurrency, &routerserverpb.RouteStateReply_Concurrency{
+			Scope:       l.scope,
+			MaxInFlight: int32(l.limit.maxInFlight),
+			InFlight:    int32(len(l.limit.slots)),
+			QueueSize:   int32(l.limit.queueSize),
+			Queued:      int32(len(l.limit.queue)),
+		})
+	}
+
+	if err := h.Health(ctx); err != nil {
+		out.RatelimitStoreError = err.Error()
+	}
+
+	if in.Key == "" {
+		return nil
+	}
+
+	for _, rl := range pr.ratelimits {
+		for _, l := range rl.limiters {
+			lctx, err := l.Peek(ctx, fmt.Sprintf("%s-%s-%s", rl.scope, l.formatted, in.Key))
+			if err != nil {
+				out.RatelimitStoreError = err.Error()
+				return nil
+			}
+
+			out.Ratelimits = append(out.Ratelimits, &routerserverpb.RouteStateReply_Ratelimit{
+				Scope:     rl.scope,
+				Key:       rl.key,
+				Rate:      l.formatted,
+				Limit:     lctx.Limit,
+				Remaining: lctx.Remaining,
+				ResetAt:   lctx.Reset,
+			})
+		}
+	}
+
+	return nil
+}
+
+// setRouteEnabled enables or disables a route on this instance
+func (h *Handler) setRouteEnabled(pathMethod string, enabled bool) {
+	h.disabledMu.Lock()
+	defer h.disabledMu.Unlock()
+
+	if enabled {
+		delete(h.disabled, pathMethod)
+	} else {
+		h.disabled[pathMethod] = true
+	}
+}
+
+// SetRouteEnabled disables or enables a route on all instances, disabled routes answer with 503 until they get enabled again
+func (h *Handler) SetRouteEnabled(ctx context.Context, in *routerserverpb.SetRouteEnabledRequest, out *routerserverpb.RouteStateReply) error {
+	pathMethod := fmt.Sprintf("%s:%s", in.Method, in.Path)
+	pr, ok := h.loadTable().routes[pathMethod]
+	if !ok {
+		return errors.NotFound(config.Name, "route not found")
+	}
+
+	// Never lock out the admin API
+	if pr.service == config.Name+"-internal" && !in.Enabled {
+		return errors.BadRequest(config.Name, "routes of the router can't be disabled")
+	}
+
+	h.setRouteEnabled(pathMethod, in.Enabled)
+
+	if err := h.publishState(ctx, &stateEvent{Route: &routeEvent{Method: in.Method, Path: in.Path, Enabled: in.Enabled}}); err != nil {
+		return errors.InternalServerError(config.Name, "changed the route on instance %s only: %s", h.instanceID, err)
+	}
+
+	return h.RouteState(ctx, &routerserverpb.RouteStateRequest{Method: in.Method, Path: in.Path}, out)
+}
+
+// resetRatelimits forgets the requests counted for key by all ratelimits of a route
+func resetRatelimits(ctx context.Context, pr *proxyRoute, key string) error {
+	for _, rl := range pr.ratelimits {
+		for _, l := range rl.limiters {
+			if _, err := l.Reset(ctx, fmt.Sprintf("%s-%s-%s", rl.scope, l.formatted, key)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ResetRatelimit forgets the requests counted for a key by all ratelimits of a route on all instances
+func (h *Handler) ResetRatelimit(ctx context.Context, in *routerserverpb.RouteStateRequest, out *routerserverpb.RouteStateReply) error {
+	if in.Key == "" {
+		return errors.BadRequest(config.Name, "key is required")
+	}
+
+	pr, ok := h.loadTable().routes[fmt.Sprintf("%s:%s", in.Method, in.Path)]
+	if !ok {
+		return errors.NotFound(config.Name, "route not found")
+	}
+
+	if err := resetRatelimits(ctx, pr, in.Key); err != nil {
+		return errors.InternalServerError(config.Name, "failed to reset the ratelimit: %s", err)
+	}
+
+	// Memory stores count per instance
+	if err := h.publishState(ctx, &stateEvent{Ratelimit: &ratelimitEvent{Method: in.Method, Path: in.Path, Key: in.Key}}); err != nil {
+		return errors.InternalServerError(config.Name, "reset the ratelimit on instance %s only: %s", h.instanceID, err)
+	}
+
+	return h.RouteState(ctx, in, out)
+}
+
+// Config returns the effective config, the flags and the config file, as YAML
+func (h *Handler) Config(ctx context.Context, in *emptypb.Empty, out *routerserverpb.ConfigReply) error {
+	data, err := yaml.Marshal(struct {
+		Flags map[string]interface{} `yaml:"flags"`
+		File  *config.File           `yaml:"file,omitempty"`
+	}{h.flags, redactedConfig(h.loadTable().config)})
+	if err != nil {
+		return errors.InternalServerError(config.Name, "failed to marshal the config: %s", err)
+	}
+
+	out.Config = string(data)
+	return nil
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jochum.dev/jo-micro/auth2"
+)
+
+// auditEvent is published to the audit topic for every request to a route with router.Audit()
+type auditEvent struct {
+	Time      string   `json:"time"`
+	UserID    string   `json:"user_id,omitempty"`
+	UserRoles []string `json:"user_roles,omitempty"`
+	Method    string   `json:"method"`
+	Path      string   `json:"path"`
+	Route     string   `json:"route"`
+	Service   string   `json:"service"`
+	Endpoint  string   `json:"endpoint,omitempty"`
+	Status    int      `json:"status"`
+	LatencyMS float64  `json:"latency_ms"`
+	ClientIP  string   `json:"client_ip"`
+}
+
+// audit publishes the audit event of a finished request, it doesn't block when the broker is slow
+func (h *Handler) audit(c *gin.Context, pr *proxyRoute, start time.Time) {
+	if h.auditPublisher == nil {
+		return
+	}
+
+	event := auditEvent{
+		Time:      start.UTC().Format(time.RFC3339Nano),
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.Path,
+		Route:     c.FullPath(),
+		Service:   pr.service,
+		Endpoint:  pr.route.Endpoint,
+		Status:    c.Writer.Status(),
+		LatencyMS: float64(time.Since(start)) / float64(time.Millisecond),
+		ClientIP:  c.ClientIP(),
+	}
+	if v, ok := c.Get(userContextKey); ok {
+		u := v.(*auth2.User)
+		event.UserID = u.Id
+		event.UserRoles = u.Roles
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	h.auditPublisher.Publish(data)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go-micro.dev/v4/broker"
+	"jochum.dev/jo-micro/auth2"
+	"jochum.dev/jo-micro/router/internal/proto/routerclientpb"
+)
+
+// recordingBroker keeps what gets published
+type recordingBroker struct {
+	broker.Broker
+
+	mu       sync.Mutex
+	topics   []string
+	messages []*broker.Message
+}
+
+func (b *recordingBroker) Publish(topic string, m *broker.Message, opts ...broker.PublishOption) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.topics = append(b.topics, topic)
+	b.messages = append(b.messages, m)
+	return nil
+}
+
+func TestAudit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	b := &recordingBroker{}
+	h := &Handler{auditPublisher: newAsyncPublisher(b, "router.audit", 8, logger)}
+	pr := &proxyRoute{service: "jo.micro.users", route: &routerclientpb.RoutesReply_Route{Endpoint: "Users.Get"}}
+
+	start := time.Now()
+	r := gin.New()
+	r.GET("/users/:id", func(c *gin.Context) {
+		c.Set(userContextKey, &auth2.User{Id: "u1", Roles: []string{"admin"}})
+		c.Status(http.StatusNoContent)
+		h.audit(c, pr, start)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Close publishes what's queued
+	if err := h.auditPublisher.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(b.messages) != 1 || b.topics[0] != "router.audit" {
+		t.Fatalf("got %d messages to %v", len(b.messages), b.topics)
+	}
+	if ct := b.messages[0].Header["Content-Type"]; ct != "application/json" {
+		t.Errorf("got Content-Type %s", ct)
+	}
+
+	var got auditEvent
+	if err := json.Unmarshal(b.messages[0].Body, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.LatencyMS < 0 {
+		t.Errorf("got latency %f", got.LatencyMS)
+	}
+	got.LatencyMS = 0
+	want := auditEvent{
+		Time:      start.UTC().Format(time.RFC3339Nano),
+		UserID:    "u1",
+		UserRoles: []string{"admin"},
+		Method:    http.MethodGet,
+		Path:      "/users/42",
+		Route:     "/users/:id",
+		Service:   "jo.micro.users",
+		Endpoint:  "Users.Get",
+		Status:    http.StatusNoContent,
+		ClientIP:  "192.0.2.1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// Without a publisher audit is a noop
+	(&Handler{}).audit(&gin.Context{}, pr, start)
+}
+
+func TestAsyncPublisherDrops(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	p := &asyncPublisher{queue: make(chan []byte, 1), logger: logger}
+
+	p.Publish([]byte("a"))
+	p.Publish([]byte("b"))
+	if p.dropped != 1 {
+		t.Errorf("got %d dropped, want 1", p.dropped)
+	}
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"jochum.dev/jo-micro/router/internal/proto/routerclientpb"
+)
+
+// bodyLimits limit what a request body may contain, 0 means no limit
+type bodyLimits struct {
+	maxBodySize          int64
+	maxMultipartParts    int
+	maxMultipartPartSize int64
+	maxJSONDepth         int
+}
+
+// errTooManyParts is returned when a multipart body has more parts than allowed
+var errTooManyParts = errors.New("too many multipart parts")
+
+// errPartTooLarge is returned when a part of a multipart body is larger than allowed
+var errPartTooLarge = errors.New("multipart part too large")
+
+// bodyLimits returns the limits of the route, the gateway limits apply where the route has none
+func (h *Handler) bodyLimits(route *routerclientpb.RoutesReply_Route) bodyLimits {
+	result := h.defaultBodyLimits
+	if route.MaxBodySize > 0 {
+		result.maxBodySize = route.MaxBodySize
+	}
+	if route.MaxMultipartParts > 0 {
+		result.maxMultipartParts = int(route.MaxMultipartParts)
+	}
+	if route.MaxMultipartPartSize > 0 {
+		result.maxMultipartPartSize = route.MaxMultipartPartSize
+	}
+	if route.MaxJSONDepth > 0 {
+		result.maxJSONDepth = int(route.MaxJSONDepth)
+	}
+
+	return result
+}
+
+// limitBody rejects bodies with a too large Content-Length and stops reading others at the limit
+func (h *Handler) limitBody(c *gin.Context, pr *proxyRoute) bool {
+	limits := h.bodyLimits(pr.route)
+	if limits.maxBodySize <= 0 {
+		return true
+	}
+
+	if c.Request.ContentLength > limits.maxBodySize {
+		abortWithError(c, http.StatusRequestEntityTooLarge, "REQUEST_ENTITY_TOO_LARGE", fmt.Sprintf("the body is larger than %d bytes", limits.maxBodySize))
+		return false
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limits.maxBodySize)
+	return true
+}
+
+// abortWithBodyError answers with 413 if a limit was hit while reading the body else with 400
+func abortWithBodyError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) || errors.Is(err, errTooManyParts) || errors.Is(err, errPartTooLarge) {
+		abortWithError(c, http.StatusRequestEntityTooLarge, "REQUEST_ENTITY_TOO_LARGE", err.Error())
+		return
+	}
+
+	abortWithError(c, http.StatusBadRequest, "BAD_REQUEST", err)
+}
+
+// readMultipart streams a multipart body into request, files are base64 encoded,
+// fields with more than one value become lists
+func readMultipart(mr *multipart.Reader, limits bodyLimits, request gin.H) error {
+	files := make(map[string][]string)
+	values := make(map[string][]string)
+
+	for parts := 1; ; parts++ {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if limits.maxMultipartParts > 0 && parts > limits.maxMultipartParts {
+			return fmt.Errorf("%w, the limit is %d", errTooManyParts, limits.maxMultipartParts)
+		}
+
+		var reader io.Reader = part
+		if limits.maxMultipartPartSize > 0 {
+			reader = io.LimitReader(part, limits.maxMultipartPartSize+1)
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		if limits.maxMultipartPartSize > 0 && int64(len(data)) > limits.maxMultipartPartSize {
+			return fmt.Errorf("%w, the limit is %d bytes", errPartTooLarge, limits.maxMultipartPartSize)
+		}
+
+		if part.FileName() != "" {
+			files[part.FormName()] = append(files[part.FormName()], base64.StdEncoding.EncodeToString(data))
+		} else {
+			values[part.FormName()] = append(values[part.FormName()], string(data))
+		}
+	}
+
+	for _, fields := range []map[string][]string{files, values} {
+		for k, v := range fields {
+			if len(v) > 1 {
+				request[k] = v
+			} else {
+				request[k] = v[0]
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkJSONDepth returns an error as soon as objects/arrays in data are nested deeper than maxDepth,
+// it doesn't validate data, that's up to the JSON decoder
+func checkJSONDepth(data []byte, maxDepth int) error {
+	if maxDepth <= 0 {
+		return nil
+	}
+
+	depth := 0
+	inString := false
+	escaped := false
+	for _, b := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case b == '\\':
+				escaped = true
+			case b == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch b {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > maxDepth {
+				return fmt.Errorf("the JSON body is nested deeper than %d levels", maxDepth)
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package handler
+
+import "testing"
+
+func TestCheckJSONDepth(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		maxDepth int
+		wantErr  bool
+	}{
+		{name: "flat", data: `{"a": 1}`, maxDepth: 1},
+		{name: "at the limit", data: `{"a": [1, {"b": 2}]}`, maxDepth: 3},
+		{name: "too deep", data: `{"a": [1, {"b": 2}]}`, maxDepth: 2, wantErr: true},
+		{name: "brackets in strings", data: `{"a": "[[[{{{"}`, maxDepth: 1},
+		{name: "escaped quote", data: `{"a": "\"[[["}`, maxDepth: 1},
+		{name: "escaped backslash", data: `{"a": "\\", "b": [[1]]}`, maxDepth: 2, wantErr: true},
+		{name: "siblings", data: `[[1], [2], [3]]`, maxDepth: 2},
+		{name: "unlimited", data: `[[[[[[1]]]]]]`, maxDepth: 0},
+		{name: "invalid JSON", data: `{{{`, maxDepth: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkJSONDepth([]byte(tt.data), tt.maxDepth)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
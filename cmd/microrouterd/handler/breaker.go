@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	breakerClosed   = "closed"
+	breakerOpen     = "open"
+	breakerHalfOpen = "half-open"
+)
+
+// circuitBreaker opens after maxFailures upstream failures in a row, after cooldown it lets one request through
+// and closes again if that one succeeds
+type circuitBreaker struct {
+	maxFailures int
+	cooldown    time.Duration
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(maxFailures int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{maxFailures: maxFailures, cooldown: cooldown, state: breakerClosed}
+}
+
+// allow reports whether a request may go to the upstream, it returns the time left until the next try if not
+func (b *circuitBreaker) allow(now time.Time) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if left := b.openedAt.Add(b.cooldown).Sub(now); left > 0 {
+			return false, left
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+		return true, 0
+	case breakerHalfOpen:
+		if b.probing {
+			return false, b.cooldown
+		}
+		b.probing = true
+		return true, 0
+	}
+
+	return true, 0
+}
+
+// done records the result of a request allow let through
+func (b *circuitBreaker) done(failed bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.probing = false
+	}
+
+	if !failed {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.maxFailures {
+		b.state = breakerOpen
+		b.openedAt = now
+	}
+}
+
+// snapshot returns the state, the failures in a row and when an open breaker lets the next request through
+func (b *circuitBreaker) snapshot() (string, int, time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerOpen {
+		return b.state, b.failures, b.openedAt.Add(b.cooldown)
+	}
+
+	return b.state, b.failures, time.Time{}
+}
+
+// breakerFor returns the breaker of a service, nil if breakers are disabled
+func (h *Handler) breakerFor(service string) *circuitBreaker {
+	if h.breakerFailures < 1 {
+		return nil
+	}
+
+	b, _ := h.breakers.LoadOrStore(service, newCircuitBreaker(h.breakerFailures, h.breakerCooldown))
+	return b.(*circuitBreaker)
+}
+
+// checkBreaker aborts with 503 while the breaker of the service is open, else it returns a func to record the result
+func (h *Handler) checkBreaker(c *gin.Context, pr *proxyRoute) (func(), bool) {
+	b := h.breakerFor(pr.service)
+	if b == nil {
+		return func() {}, true
+	}
+
+	ok, retryAfter := b.allow(time.Now())
+	if !ok {
+		c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+		abortWithError(c, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "service unavailable")
+		return nil, false
+	}
+
+	return func() {
+		b.done(c.Writer.Status() >= http.StatusInternalServerError, time.Now())
+	}, true
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	b := newCircuitBreaker(2, time.Minute)
+	now := time.Now()
+
+	steps := []struct {
+		name    string
+		at      time.Duration
+		allowed bool
+		failed  bool
+		state   string
+	}{
+		{name: "first failure", allowed: true, failed: true, state: breakerClosed},
+		{name: "success resets", allowed: true, state: breakerClosed},
+		{name: "failure", allowed: true, failed: true, state: breakerClosed},
+		{name: "opens", allowed: true, failed: true, state: breakerOpen},
+		{name: "fails fast", at: 30 * time.Second, state: breakerOpen},
+		{name: "probe fails", at: time.Minute, allowed: true, failed: true, state: breakerOpen},
+		{name: "fails fast again", at: 90 * time.Second, state: breakerOpen},
+		{name: "probe succeeds", at: 2 * time.Minute, allowed: true, state: breakerClosed},
+	}
+
+	for _, s := range steps {
+		at := now.Add(s.at)
+		allowed, _ := b.allow(at)
+		if allowed != s.allowed {
+			t.Fatalf("%s: got allowed %v, want %v", s.name, allowed, s.allowed)
+		}
+		if allowed {
+			b.done(s.failed, at)
+		}
+		if state, _, _ := b.snapshot(); state != s.state {
+			t.Fatalf("%s: got state %s, want %s", s.name, state, s.state)
+		}
+	}
+}
+
+func TestCircuitBreakerOneProbe(t *testing.T) {
+	b := newCircuitBreaker(1, time.Minute)
+	now := time.Now()
+
+	b.allow(now)
+	b.done(true, now)
+
+	if ok, _ := b.allow(now.Add(time.Minute)); !ok {
+		t.Fatal("the probe wasn't let through")
+	}
+	if ok, _ := b.allow(now.Add(time.Minute)); ok {
+		t.Error("a second request got through while probing")
+	}
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jochum.dev/jo-micro/router/cmd/microrouterd/config"
+	"jochum.dev/jo-micro/router/internal/proto/routerclientpb"
+)
+
+// concurrencyLimit limits the requests in flight, up to queueSize requests wait queueTimeout for a free slot
+type concurrencyLimit struct {
+	maxInFlight  int
+	queueSize    int
+	queueTimeout time.Duration
+	slots        chan struct{}
+	queue        chan struct{}
+}
+
+func newConcurrencyLimit(maxInFlight, queueSize int, queueTimeout time.Duration) *concurrencyLimit {
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	return &concurrencyLimit{
+		maxInFlight:  maxInFlight,
+		queueSize:    queueSize,
+		queueTimeout: queueTimeout,
+		slots:        make(chan struct{}, maxInFlight),
+		queue:        make(chan struct{}, queueSize),
+	}
+}
+
+// reuse returns l if it has the given settings so in flight requests keep counting, else a new limit
+func (l *concurrencyLimit) reuse(maxInFlight, queueSize int, queueTimeout time.Duration) *concurrencyLimit {
+	if maxInFlight < 1 {
+		return nil
+	}
+
+	if l != nil && l.maxInFlight == maxInFlight && l.queueSize == queueSize && l.queueTimeout == queueTimeout {
+		return l
+	}
+
+	return newConcurrencyLimit(maxInFlight, queueSize, queueTimeout)
+}
+
+// acquire takes a slot, it returns false if the queue is full or the wait timed out
+func (l *concurrencyLimit) acquire(ctx context.Context) bool {
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if l.queueTimeout <= 0 {
+		return false
+	}
+
+	select {
+	case l.queue <- struct{}{}:
+		defer func() { <-l.queue }()
+	default:
+		return false
+	}
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (l *concurrencyLimit) release() {
+	<-l.slots
+}
+
+// queueTimeoutOr returns queueTimeout, or defaultTimeout if it's not set so queued requests never wait forever
+func queueTimeoutOr(queueTimeout, defaultTimeout time.Duration) time.Duration {
+	if queueTimeout <= 0 {
+		return defaultTimeout
+	}
+
+	return queueTimeout
+}
+
+// routeConcurrencyLimit returns the limit for a route, it keeps the limit of the route it replaces if nothing changed
+func routeConcurrencyLimit(existing *proxyRoute, route *routerclientpb.RoutesReply_Route, defaultQueueTimeout time.Duration) *concurrencyLimit {
+	var l *concurrencyLimit
+	if existing != nil {
+		l = existing.concurrency
+	}
+
+	return l.reuse(int(route.MaxInFlight), int(route.QueueSize), queueTimeoutOr(time.Duration(route.QueueTimeoutMs)*time.Millisecond, defaultQueueTimeout))
+}
+
+// parseServiceConcurrency parses "router_service_concurrency" flags, format: <service>=<maxInFlight>[:<queueSize>[:<queueTimeout>]]
+func parseServiceConcurrency(flags []string) ([]config.ServiceConcurrency, error) {
+	result := make([]config.ServiceConcurrency, 0, len(flags))
+	for _, f := range flags {
+		service, spec, ok := strings.Cut(f, "=")
+		if !ok || service == "" {
+			return nil, fmt.Errorf("invalid service concurrency '%s'", f)
+		}
+
+		parts := strings.SplitN(spec, ":", 3)
+		c := config.ServiceConcurrency{Service: service}
+		var err error
+		if c.MaxInFlight, err = strconv.Atoi(parts[0]); err != nil {
+			return nil, fmt.Errorf("invalid maxInFlight in service concurrency '%s'", f)
+		}
+		if len(parts) > 1 {
+			if c.QueueSize, err = strconv.Atoi(parts[1]); err != nil {
+				return nil, fmt.Errorf("invalid queueSize in service concurrency '%s'", f)
+			}
+		}
+		if len(parts) > 2 {
+			if c.QueueTimeout, err = time.ParseDuration(parts[2]); err != nil {
+				return nil, fmt.Errorf("invalid queueTimeout in service concurrency '%s'", f)
+			}
+		}
+
+		result = append(result, c)
+	}
+
+	return result, nil
+}
+
+// updateServiceConcurrency builds the per service limits from the flags and the config file, the config file wins
+func (h *Handler) updateServiceConcurrency() {
+	current, _ := h.serviceConcurrency.Load().(map[string]*concurrencyLimit)
+
+	settings := append([]config.ServiceConcurrency{}, h.concurrencyFlags...)
+	if h.config != nil {
+		settings = append(settings, h.config.Concurrency...)
+	}
+
+	limits := make(map[string]*concurrencyLimit)
+	for _, c := range settings {
+		limits[c.Service] = current[c.Service].reuse(c.MaxInFlight, c.QueueSize, queueTimeoutOr(c.QueueTimeout, h.queueTimeout))
+	}
+
+	h.serviceConcurrency.Store(limits)
+}
+
+// acquireConcurrency takes a slot of the route and of its service, it aborts with 503 when that's not possible
+func (h *Handler) acquireConcurrency(c *gin.Context, pr *proxyRoute) (func(), bool) {
+	ctx := c.Request.Context()
+
+	acquired := []*concurrencyLimit{}
+	release := func() {
+		for i := len(acquired) - 1; i >= 0; i-- {
+			acquired[i].release()
+		}
+	}
+
+	limits, _ := h.serviceConcurrency.Load().(map[string]*concurrencyLimit)
+	for _, l := range []*concurrencyLimit{pr.concurrency, limits[pr.service]} {
+		if l == nil {
+			continue
+		}
+
+		if !l.acquire(ctx) {
+			release()
+			c.Header("Retry-After", "1")
+			abortWithError(c, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "too many concurrent requests")
+			return nil, false
+		}
+		acquired = append(acquired, l)
+	}
+
+	return release, true
+}
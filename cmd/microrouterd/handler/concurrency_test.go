@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"jochum.dev/jo-micro/router/cmd/microrouterd/config"
+)
+
+func TestConcurrencyLimitAcquire(t *testing.T) {
+	t.Run("queue timeout", func(t *testing.T) {
+		l := newConcurrencyLimit(1, 1, 50*time.Millisecond)
+		if !l.acquire(context.Background()) {
+			t.Fatal("didn't get a free slot")
+		}
+
+		start := time.Now()
+		if l.acquire(context.Background()) {
+			t.Fatal("got a slot while all are taken")
+		}
+		if waited := time.Since(start); waited < 50*time.Millisecond {
+			t.Errorf("gave up after %s, before the queue timeout", waited)
+		}
+
+		l.release()
+		if !l.acquire(context.Background()) {
+			t.Error("didn't get the released slot")
+		}
+	})
+
+	t.Run("full queue", func(t *testing.T) {
+		l := newConcurrencyLimit(1, 1, time.Minute)
+		if !l.acquire(context.Background()) {
+			t.Fatal("didn't get a free slot")
+		}
+
+		queued := make(chan bool)
+		go func() {
+			queued <- l.acquire(context.Background())
+		}()
+		// Wait until the request is queued
+		for len(l.queue) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+
+		start := time.Now()
+		if l.acquire(context.Background()) {
+			t.Fatal("got a slot with a full queue")
+		}
+		if waited := time.Since(start); waited > time.Second {
+			t.Errorf("waited %s with a full queue", waited)
+		}
+
+		l.release()
+		if !<-queued {
+			t.Error("the queued request didn't get the released slot")
+		}
+	})
+
+	t.Run("no queue", func(t *testing.T) {
+		l := newConcurrencyLimit(1, 0, time.Minute)
+		if !l.acquire(context.Background()) || l.acquire(context.Background()) {
+			t.Error("without a queue only one request may get a slot")
+		}
+	})
+
+	t.Run("request canceled", func(t *testing.T) {
+		l := newConcurrencyLimit(1, 1, time.Minute)
+		l.acquire(context.Background())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if l.acquire(ctx) {
+			t.Error("got a slot after the request was canceled")
+		}
+	})
+}
+
+func TestParseServiceConcurrency(t *testing.T) {
+	got, err := parseServiceConcurrency([]string{"a=10", "b=20:50", "c=5:10:2s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []config.ServiceConcurrency{
+		{Service: "a", MaxInFlight: 10},
+		{Service: "b", MaxInFlight: 20, QueueSize: 50},
+		{Service: "c", MaxInFlight: 5, QueueSize: 10, QueueTimeout: 2 * time.Second},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	for _, f := range []string{"a", "=10", "a=x", "a=10:x", "a=10:5:x"} {
+		if _, err := parseServiceConcurrency([]string{f}); err == nil {
+			t.Errorf("%s: no error", f)
+		}
+	}
+}
+
+func TestUpdateServiceConcurrency(t *testing.T) {
+	h := &Handler{
+		queueTimeout:     time.Second,
+		concurrencyFlags: []config.ServiceConcurrency{{Service: "a", MaxInFlight: 1, QueueSize: 1}, {Service: "b", MaxInFlight: 1}},
+		config:           &config.File{Concurrency: []config.ServiceConcurrency{{Service: "b", MaxInFlight: 2, QueueTimeout: 5 * time.Second}}},
+	}
+	h.updateServiceConcurrency()
+
+	limits := h.serviceConcurrency.Load().(map[string]*concurrencyLimit)
+	if l := limits["a"]; l == nil || l.queueTimeout != time.Second {
+		t.Errorf("a: got %+v, want the default queue timeout", l)
+	}
+	if l := limits["b"]; l == nil || l.maxInFlight != 2 || l.queueTimeout != 5*time.Second {
+		t.Errorf("b: got %+v, want the limit of the config file", l)
+	}
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go-micro.dev/v4/errors"
+)
+
+const errorFormatterContextKey = "router.errorFormatter"
+
+const (
+	errorFormatEnvelope = "envelope" // {"errors":[{"id":"NOT_FOUND","message":"page not found"}]}
+	errorFormatProblem  = "problem"  // RFC 7807 application/problem+json
+)
+
+// defaultErrorStatus maps the codes of go-micro client errors, the service didn't answer, to what the gateway answers
+var defaultErrorStatus = map[int]int{
+	http.StatusRequestTimeout:      http.StatusGatewayTimeout,
+	http.StatusInternalServerError: http.StatusBadGateway,
+}
+
+// errorFormatter writes errors in the envelope or as problem+json, in production it hides the details of 5xx errors,
+// status maps the codes of all upstream errors, clientStatus those of go-micro client errors
+type errorFormatter struct {
+	format       string
+	redact       bool
+	status       map[int]int
+	clientStatus map[int]int
+}
+
+func newErrorFormatter(format string, redact bool, statusFlags []string) (*errorFormatter, error) {
+	switch format {
+	case errorFormatEnvelope, errorFormatProblem:
+	default:
+		return nil, fmt.Errorf("unknown error format '%s'", format)
+	}
+
+	f := &errorFormatter{format: format, redact: redact, status: make(map[int]int), clientStatus: defaultErrorStatus}
+
+	// "<go-micro code>=<http status>"
+	for _, flag := range statusFlags {
+		from, to, ok := strings.Cut(flag, "=")
+		fromCode, err1 := strconv.Atoi(strings.TrimSpace(from))
+		toCode, err2 := strconv.Atoi(strings.TrimSpace(to))
+		if !ok || err1 != nil || err2 != nil || toCode < 400 || toCode > 599 {
+			return nil, fmt.Errorf("invalid error status mapping '%s'", flag)
+		}
+		f.status[fromCode] = toCode
+	}
+
+	return f, nil
+}
+
+// statusID returns the id for a HTTP status, for example NOT_FOUND
+func statusID(code int) string {
+	return strings.ToUpper(strings.ReplaceAll(http.StatusText(code), " ", "_"))
+}
+
+// upstreamError maps an error from calling a service to a HTTP status, its id and its message, the id of the service
+// stays, the status map of the formatter applies to all errors, the defaults only to errors of the go-micro client
+func upstreamError(c *gin.Context, err error) (int, string, string) {
+	pErr := errors.FromError(err)
+
+	code := int(pErr.Code)
+	if code < 400 || code > 599 {
+		code = http.StatusInternalServerError
+	}
+
+	status, clientStatus := map[int]int{}, defaultErrorStatus
+	if f, ok := c.Value(errorFormatterContextKey).(*errorFormatter); ok {
+		status, clientStatus = f.status, f.clientStatus
+	}
+	if mapped, ok := status[code]; ok {
+		code = mapped
+	} else if mapped, ok := clientStatus[code]; ok && pErr.Id == "go.micro.client" {
+		code = mapped
+	}
+
+	id := pErr.Id
+	if id == "" {
+		id = statusID(code)
+	}
+
+	return code, id, pErr.Detail
+}
+
+// errorMessage makes a message out of whatever the handlers pass, nil becomes the status text
+func errorMessage(code int, message interface{}) string {
+	switch m := message.(type) {
+	case nil:
+		return http.StatusText(code)
+	case error:
+		return m.Error()
+	case string:
+		if m == "" {
+			return http.StatusText(code)
+		}
+		return m
+	default:
+		return fmt.Sprint(m)
+	}
+}
+
+// abortWithError writes the errors envelope or problem+json, or a gRPC status for gRPC clients, and aborts the request
+func abortWithError(c *gin.Context, code int, id string, message interface{}) {
+	f, _ := c.Value(errorFormatterContextKey).(*errorFormatter)
+
+	// Ids and messages of 5xx errors are internal
+	msg := errorMessage(code, message)
+	if f != nil && f.redact && code >= http.StatusInternalServerError {
+		id = statusID(code)
+		msg = http.StatusText(code)
+	}
+
+	if c.GetBool(grpcContextKey) {
+		writeGRPC(c, nil, grpcStatus(code), msg)
+		c.Abort()
+		return
+	}
+
+	if f != nil && f.format == errorFormatProblem {
+		writeProblem(c, code, gin.H{"code": id, "detail": msg})
+		return
+	}
+
+	c.JSON(code, gin.H{
+		"errors": []gin.H{
+			{
+				"id":      id,
+				"message": msg,
+			},
+		},
+	})
+	c.Abort()
+}
+
+// writeProblem writes a RFC 7807 problem with the extra members and aborts the request
+func writeProblem(c *gin.Context, code int, members gin.H) {
+	problem := gin.H{
+		"type":   "about:blank",
+		"title":  http.StatusText(code),
+		"status": code,
+	}
+	for k, v := range members {
+		problem[k] = v
+	}
+
+	data, err := json.Marshal(problem)
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	c.Data(code, "application/problem+json", data)
+	c.Abort()
+}
+
+// fieldError is an error for a single field of the request, field is its dotted path
+type fieldError struct {
+	field   string
+	message string
+}
+
+// abortWithFieldErrors answers with 400 and one entry per field error in the errors envelope,
+// problem+json has them in "invalid-params"
+func abortWithFieldErrors(c *gin.Context, errs []fieldError) {
+	if c.GetBool(grpcContextKey) {
+		messages := make([]string, len(errs))
+		for idx, e := range errs {
+			messages[idx] = strings.TrimPrefix(e.field+": "+e.message, ": ")
+		}
+		writeGRPC(c, nil, grpcStatus(http.StatusBadRequest), strings.Join(messages, "; "))
+		c.Abort()
+		return
+	}
+
+	if f, ok := c.Value(errorFormatterContextKey).(*errorFormatter); ok && f.format == errorFormatProblem {
+		params := make([]gin.H, len(errs))
+		for idx, e := range errs {
+			params[idx] = gin.H{"name": e.field, "reason": e.message}
+		}
+		writeProblem(c, http.StatusBadRequest, gin.H{"code": "BAD_REQUEST", "detail": "the request is invalid", "invalid-params": params})
+		return
+	}
+
+	result := make([]gin.H, len(errs))
+	for idx, e := range errs {
+		result[idx] = gin.H{
+			"id":      "BAD_REQUEST",
+			"message": e.message,
+			"field":   e.field,
+		}
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"errors": result})
+	c.Abort()
+}
+
+// NoRoute answers requests without a route with NOT_FOUND in the configured format
+func (h *Handler) NoRoute(c *gin.Context) {
+	c.Set(errorFormatterContextKey, h.errorFormatter)
+	abortWithError(c, http.StatusNotFound, "NOT_FOUND", "page not found")
+}
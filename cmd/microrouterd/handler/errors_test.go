@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go-micro.dev/v4/errors"
+)
+
+func TestUpstreamError(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusFlags []string
+		err         error
+		code        int
+		id          string
+		detail      string
+	}{
+		{name: "service error", err: errors.NotFound("users.NotFound", "user not found"), code: http.StatusNotFound, id: "users.NotFound", detail: "user not found"},
+		{name: "without an id", err: &errors.Error{Code: http.StatusConflict, Detail: "exists"}, code: http.StatusConflict, id: "CONFLICT", detail: "exists"},
+		{name: "no code", err: fmt.Errorf("boom"), code: http.StatusInternalServerError, id: "INTERNAL_SERVER_ERROR", detail: "boom"},
+		{name: "client timeout", err: errors.Timeout("go.micro.client", "request timeout"), code: http.StatusGatewayTimeout, id: "go.micro.client", detail: "request timeout"},
+		{name: "client error", err: errors.InternalServerError("go.micro.client", "connection refused"), code: http.StatusBadGateway, id: "go.micro.client", detail: "connection refused"},
+		{name: "service 500 isn't a client error", err: errors.InternalServerError("users", "boom"), code: http.StatusInternalServerError, id: "users", detail: "boom"},
+		{name: "mapped service error", statusFlags: []string{"501=500"}, err: errors.New("users", "not implemented", 501), code: http.StatusInternalServerError, id: "users", detail: "not implemented"},
+		{name: "mapping wins over the client defaults", statusFlags: []string{"408=503"}, err: errors.Timeout("go.micro.client", "request timeout"), code: http.StatusServiceUnavailable, id: "go.micro.client", detail: "request timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newErrorFormatter(errorFormatEnvelope, false, tt.statusFlags)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Set(errorFormatterContextKey, f)
+
+			code, id, detail := upstreamError(c, tt.err)
+			if code != tt.code || id != tt.id || detail != tt.detail {
+				t.Errorf("got %d %s %q, want %d %s %q", code, id, detail, tt.code, tt.id, tt.detail)
+			}
+		})
+	}
+}
+
+func TestAbortWithErrorRedacts(t *testing.T) {
+	tests := []struct {
+		name    string
+		redact  bool
+		code    int
+		id      string
+		message string
+		wantID  string
+		wantMsg string
+	}{
+		{name: "internal error", redact: true, code: http.StatusBadGateway, id: "go.micro.client", message: "dial tcp 10.0.0.3:8080: connection refused", wantID: "BAD_GATEWAY", wantMsg: "Bad Gateway"},
+		{name: "debugmode", code: http.StatusBadGateway, id: "go.micro.client", message: "connection refused", wantID: "go.micro.client", wantMsg: "connection refused"},
+		{name: "client error", redact: true, code: http.StatusNotFound, id: "users.NotFound", message: "user not found", wantID: "users.NotFound", wantMsg: "user not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newErrorFormatter(errorFormatEnvelope, tt.redact, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set(errorFormatterContextKey, f)
+
+			abortWithError(c, tt.code, tt.id, tt.message)
+
+			var body struct {
+				Errors []struct {
+					ID      string `json:"id"`
+					Message string `json:"message"`
+				} `json:"errors"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || len(body.Errors) != 1 {
+				t.Fatalf("invalid body %s", w.Body)
+			}
+			if got := body.Errors[0]; got.ID != tt.wantID || got.Message != tt.wantMsg {
+				t.Errorf("got %s %q, want %s %q", got.ID, got.Message, tt.wantID, tt.wantMsg)
+			}
+		})
+	}
+}
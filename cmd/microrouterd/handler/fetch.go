@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-micro.dev/v4/registry"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"jochum.dev/jo-micro/auth2"
+	"jochum.dev/jo-micro/logruscomponent"
+	"jochum.dev/jo-micro/router/internal/proto/routerclientpb"
+)
+
+// maxFetchBackoff is the longest a failing service waits for its next try
+const maxFetchBackoff = 5 * time.Minute
+
+// fetchResult are the routes of a service or why it didn't send them
+type fetchResult struct {
+	service *registry.Service
+	resp    *routerclientpb.RoutesReply
+	err     error
+}
+
+// fetchFailure is the backoff state of a service that failed to send its routes
+type fetchFailure struct {
+	failures int
+	retryAt  time.Time
+}
+
+// fetchRoutes asks the services for their routes, at most refreshConcurrency at a time and each within refreshTimeout,
+// services in backoff are skipped
+func (h *Handler) fetchRoutes(ctx context.Context, services []*registry.Service) []fetchResult {
+	now := time.Now()
+	results := make([]fetchResult, 0, len(services))
+	for _, s := range services {
+		if f, ok := h.fetchFailures[s.Name]; ok && now.Before(f.retryAt) {
+			continue
+		}
+		results = append(results, fetchResult{service: s})
+	}
+
+	concurrency := h.refreshConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for idx := range results {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r *fetchResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			r.resp, r.err = h.fetchServiceRoutes(ctx, r.service)
+		}(&results[idx])
+	}
+	wg.Wait()
+
+	h.updateFetchFailures(services, results)
+
+	return results
+}
+
+func (h *Handler) fetchServiceRoutes(ctx context.Context, s *registry.Service) (*routerclientpb.RoutesReply, error) {
+	if h.refreshTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.refreshTimeout)
+		defer cancel()
+	}
+
+	sCtx, err := auth2.ClientAuthMustReg(h.cReg).Plugin().ServiceContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	client := routerclientpb.NewRouterClientService(s.Name, h.cReg.Service().Client())
+	return client.Routes(sCtx, &emptypb.Empty{})
+}
+
+// updateFetchFailures backs off services that failed, it logs when a service starts failing and when it recovers
+func (h *Handler) updateFetchFailures(services []*registry.Service, results []fetchResult) {
+	logger := logruscomponent.MustReg(h.cReg).Logger()
+	now := time.Now()
+
+	for _, r := range results {
+		f, failing := h.fetchFailures[r.service.Name]
+		if r.err == nil {
+			if failing {
+				logger.WithField("service", r.service.Name).WithField("failures", f.failures).Info("service sends its routes again")
+				delete(h.fetchFailures, r.service.Name)
+			}
+			continue
+		}
+
+		if !failing {
+			f = &fetchFailure{}
+			h.fetchFailures[r.service.Name] = f
+			logger.WithField("service", r.service.Name).WithField("error", r.err).Error("failed to get the routes of the service, backing off")
+		} else {
+			logger.WithField("service", r.service.Name).WithField("error", r.err).WithField("failures", f.failures+1).Debug("failed to get the routes of the service")
+		}
+
+		f.failures++
+		backoff := time.Duration(h.refreshSeconds) * time.Second
+		for i := 1; i < f.failures && backoff < maxFetchBackoff; i++ {
+			backoff *= 2
+		}
+		if backoff > maxFetchBackoff {
+			backoff = maxFetchBackoff
+		}
+		f.retryAt = now.Add(backoff)
+	}
+
+	// Forget services that left the registry
+	known := make(map[string]bool, len(services))
+	for _, s := range services {
+		known[s.Name] = true
+	}
+	for name := range h.fetchFailures {
+		if !known[name] {
+			delete(h.fetchFailures, name)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-micro.dev/v4/registry"
+)
+
+func TestUpdateFetchFailures(t *testing.T) {
+	h := newTestHandler(t)
+	h.refreshSeconds = 10
+
+	users := &registry.Service{Name: "users"}
+	reports := &registry.Service{Name: "reports"}
+	services := []*registry.Service{users, reports}
+	errFetch := errors.New("timeout")
+
+	// backoffOf returns how long users waits for its next try
+	backoffOf := func() time.Duration {
+		f, ok := h.fetchFailures["users"]
+		if !ok {
+			t.Fatal("users isn't backed off")
+		}
+		return time.Until(f.retryAt).Round(time.Second)
+	}
+
+	for _, want := range []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, 80 * time.Second, 160 * time.Second, maxFetchBackoff, maxFetchBackoff} {
+		h.updateFetchFailures(services, []fetchResult{{service: users, err: errFetch}, {service: reports}})
+		if got := backoffOf(); got != want {
+			t.Errorf("after %d failures: got %s, want %s", h.fetchFailures["users"].failures, got, want)
+		}
+	}
+	if _, ok := h.fetchFailures["reports"]; ok {
+		t.Error("reports is backed off without failing")
+	}
+
+	// A success resets the backoff
+	h.updateFetchFailures(services, []fetchResult{{service: users}})
+	if _, ok := h.fetchFailures["users"]; ok {
+		t.Error("users is still backed off after it sent its routes")
+	}
+
+	// Services that left the registry are forgotten
+	h.updateFetchFailures(services, []fetchResult{{service: users, err: errFetch}})
+	h.updateFetchFailures([]*registry.Service{reports}, nil)
+	if _, ok := h.fetchFailures["users"]; ok {
+		t.Error("users is still backed off after it left the registry")
+	}
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go-micro.dev/v4/client"
+	raw "go-micro.dev/v4/codec/bytes"
+	"jochum.dev/jo-micro/logruscomponent"
+)
+
+const (
+	grpcContextKey = "router.grpc"
+
+	grpcContentType        = "application/grpc"
+	grpcWebContentType     = "application/grpc-web"
+	grpcWebTextContentType = "application/grpc-web-text"
+
+	// https://github.com/grpc/grpc/blob/master/doc/statuscodes.md
+	grpcOK                 = 0
+	grpcCanceled           = 1
+	grpcUnknown            = 2
+	grpcInvalidArgument    = 3
+	grpcDeadlineExceeded   = 4
+	grpcNotFound           = 5
+	grpcAlreadyExists      = 6
+	grpcPermissionDenied   = 7
+	grpcResourceExhausted  = 8
+	grpcFailedPrecondition = 9
+	grpcUnimplemented      = 12
+	grpcInternal           = 13
+	grpcUnavailable        = 14
+	grpcUnauthenticated    = 16
+)
+
+// isGRPC reports whether the client speaks gRPC or gRPC-Web, both content types start with "application/grpc"
+func isGRPC(c *gin.Context) bool {
+	return strings.HasPrefix(c.ContentType(), grpcContentType)
+}
+
+// grpcStatus maps a HTTP status to a gRPC status code
+func grpcStatus(code int) int {
+	switch code {
+	case http.StatusOK:
+		return grpcOK
+	case http.StatusBadRequest, http.StatusUnsupportedMediaType:
+		return grpcInvalidArgument
+	case http.StatusUnauthorized:
+		return grpcUnauthenticated
+	case http.StatusForbidden:
+		return grpcPermissionDenied
+	case http.StatusNotFound:
+		return grpcNotFound
+	case http.StatusConflict:
+		return grpcAlreadyExists
+	case http.StatusPreconditionFailed:
+		return grpcFailedPrecondition
+	case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+		return grpcResourceExhausted
+	case 499:
+		return grpcCanceled
+	case http.StatusInternalServerError:
+		return grpcInternal
+	case http.StatusNotImplemented:
+		return grpcUnimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return grpcUnavailable
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return grpcDeadlineExceeded
+	default:
+		return grpcUnknown
+	}
+}
+
+// callGRPC reads a single gRPC message and calls the endpoint with the protobuf codec
+func (h *Handler) callGRPC(ctx context.Context, c *gin.Context, pr *proxyRoute) {
+	logger := logruscomponent.MustReg(h.cReg).Logger()
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		abortWithBodyError(c, err)
+		return
+	}
+	if strings.HasPrefix(c.ContentType(), grpcWebTextContentType) {
+		body, err = base64.StdEncoding.DecodeString(string(body))
+		if err != nil {
+			abortWithError(c, http.StatusBadRequest, "BAD_REQUEST", err)
+			return
+		}
+	}
+
+	msg, err := readGRPCFrame(body)
+	if err != nil {
+		abortWithError(c, http.StatusBadRequest, "BAD_REQUEST", err)
+		return
+	}
+
+	req := h.cReg.Service().Client().NewRequest(pr.service, pr.route.Endpoint, &raw.Frame{Data: msg}, client.WithContentType("application/protobuf"))
+
+	// remote call
+	response := &raw.Frame{}
+	if err := h.cReg.Service().Client().Call(ctx, req, response); err != nil {
+		logger.WithField("service", pr.service).WithField("endpoint", pr.route.Endpoint).Error(err)
+
+		code, id, detail := upstreamError(c, err)
+		abortWithError(c, code, id, detail)
+		return
+	}
+
+	writeGRPC(c, response.Data, grpcOK, "")
+}
+
+// readGRPCFrame returns the message of the first length prefixed gRPC frame in data
+func readGRPCFrame(data []byte) ([]byte, error) {
+	if len(data) < 5 {
+		return nil, fmt.Errorf("short gRPC frame")
+	}
+	if data[0]&0x01 != 0 {
+		return nil, fmt.Errorf("compressed gRPC messages are not supported")
+	}
+
+	length := binary.BigEndian.Uint32(data[1:5])
+	if uint32(len(data)-5) < length {
+		return nil, fmt.Errorf("gRPC frame is shorter than its length prefix")
+	}
+
+	return data[5 : 5+length], nil
+}
+
+// grpcFrame prefixes msg with the gRPC frame header
+func grpcFrame(flags byte, msg []byte) []byte {
+	frame := make([]byte, 5+len(msg))
+	frame[0] = flags
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(msg)))
+	copy(frame[5:], msg)
+	return frame
+}
+
+// writeGRPC writes msg (if any) and the status, as HTTP/2 trailers for gRPC and as trailer frame for gRPC-Web
+func writeGRPC(c *gin.Context, msg []byte, status int, message string) {
+	contentType := c.ContentType()
+
+	var body []byte
+	if msg != nil {
+		body = grpcFrame(0x00, msg)
+	}
+
+	if !strings.HasPrefix(contentType, grpcWebContentType) {
+		c.Header("Content-Type", "application/grpc+proto")
+		c.Header("Trailer", "Grpc-Status, Grpc-Message")
+		c.Status(http.StatusOK)
+		c.Writer.Write(body)
+		c.Writer.Header().Set("Grpc-Status", strconv.Itoa(status))
+		c.Writer.Header().Set("Grpc-Message", grpcEncodeMessage(message))
+		return
+	}
+
+	trailer := fmt.Sprintf("grpc-status:%d\r\ngrpc-message:%s\r\n", status, grpcEncodeMessage(message))
+	body = append(body, grpcFrame(0x80, []byte(trailer))...)
+
+	if strings.HasPrefix(contentType, grpcWebTextContentType) {
+		c.Data(http.StatusOK, "application/grpc-web-text+proto", []byte(base64.StdEncoding.EncodeToString(body)))
+		return
+	}
+	c.Data(http.StatusOK, "application/grpc-web+proto", body)
+}
+
+// grpcEncodeMessage percent encodes a grpc-message as the spec wants it
+func grpcEncodeMessage(message string) string {
+	var sb strings.Builder
+	for i := 0; i < len(message); i++ {
+		b := message[i]
+		if b < 0x20 || b > 0x7E || b == '%' {
+			fmt.Fprintf(&sb, "%%%02X", b)
+			continue
+		}
+		sb.WriteByte(b)
+	}
+	return sb.String()
+}
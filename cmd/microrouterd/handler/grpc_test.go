@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReadGRPCFrame(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []byte
+		wantErr bool
+	}{
+		{name: "message", data: grpcFrame(0, []byte("hello")), want: []byte("hello")},
+		{name: "empty message", data: grpcFrame(0, nil), want: []byte{}},
+		{name: "trailing data", data: append(grpcFrame(0, []byte("a")), grpcFrame(0x80, []byte("b"))...), want: []byte("a")},
+		{name: "short header", data: []byte{0, 0, 0}, wantErr: true},
+		{name: "compressed", data: grpcFrame(1, []byte("hello")), wantErr: true},
+		{name: "truncated", data: grpcFrame(0, []byte("hello"))[:7], wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readGRPCFrame(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,32 +1,44 @@
 package handler
 
 import (
+	"bytes"
 	"context"
-	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
+	"net/url"
+	"os"
+	"os/signal"
+	gopath "path"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	libredis "github.com/go-redis/redis/v8"
 
 	limiter "github.com/ulule/limiter/v3"
-	"github.com/ulule/limiter/v3/drivers/store/memory"
 	sredis "github.com/ulule/limiter/v3/drivers/store/redis"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/urfave/cli/v2"
 	"go-micro.dev/v4/client"
-	"go-micro.dev/v4/errors"
 	"go-micro.dev/v4/logger"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"jochum.dev/jo-micro/auth2"
 	"jochum.dev/jo-micro/auth2/plugins/verifier/endpointroles"
 	"jochum.dev/jo-micro/components"
 	"jochum.dev/jo-micro/logruscomponent"
 	"jochum.dev/jo-micro/router"
+	"jochum.dev/jo-micro/router/cmd/microrouterd/config"
 	"jochum.dev/jo-micro/router/internal/proto/routerclientpb"
 	"jochum.dev/jo-micro/router/internal/proto/routerserverpb"
 	"jochum.dev/jo-micro/router/internal/util"
@@ -37,25 +49,152 @@ type JSONRoute struct {
 	Path   string `json:"path"`
 }
 
+// proxyRoute is a route registered with gin and everything needed to proxy it
+type proxyRoute struct {
+	service     string
+	basePath    string
+	route       *routerclientpb.RoutesReply_Route
+	definition  *routerclientpb.RoutesReply_Route // the route as its owner sent it
+	static      bool
+	upstream    *url.URL
+	rewrites    []*rewriteRule
+	ratelimits  []*ratelimit
+	concurrency *concurrencyLimit
+	access      *ipAccess
+	schema      *jsonschema.Schema
+	generation  int
+
+	version      string
+	registeredAt time.Time
+	lastSeen     atomic.Int64 // unix nano
+}
+
+// routeTable is what requests and RPCs see of the routes, the refresh goroutine replaces it after every refresh
+type routeTable struct {
+	routes map[string]*proxyRoute
+	engine *gin.Engine
+	config *config.File
+}
+
 // Handler is the handler for the proxy
 type Handler struct {
-	cReg    *components.Registry
-	engine  *gin.Engine
-	routes  map[string]*routerclientpb.RoutesReply_Route
-	rlStore limiter.Store
+	cReg *components.Registry
+
+	// The refresh goroutine owns these, everyone else uses table
+	newEngine  func() (*gin.Engine, error)
+	engine     *gin.Engine
+	nextEngine *gin.Engine
+	routes     map[string]*proxyRoute
+	registered map[string]bool
+	table      atomic.Value
+
+	rlStore         limiter.Store
+	rlBuckets       bucketStore
+	rlFailover      *failoverStore
+	rlFailOpen      bool
+	rlFailClosed    bool
+	rlLegacyHeaders bool
+	rlPolicyFlags   map[string][]string
+	rlPolicies      *ratelimitPolicies
+	refreshSeconds  int
+	configFile      string
+	config          *config.File
+	reload          chan struct{}
+	configWatcher   *fsnotify.Watcher
+	configSignals   chan os.Signal
+	generation      int
+	rewriteFlags    []string
+	globalRewrites  []*rewriteRule
+	rewrites        atomic.Value
+
+	rejectedRewrites map[*rewriteRule]bool
+
+	serviceConcurrency atomic.Value
+	concurrencyFlags   []config.ServiceConcurrency
+	queueTimeout       time.Duration
+
+	defaultBodyLimits bodyLimits
+
+	errorFormatter *errorFormatter
+
+	auditPublisher *asyncPublisher
+
+	initialized atomic.Bool
+	refreshed   atomic.Bool
+	draining    atomic.Bool
+	debugmode   bool
+
+	refreshConcurrency int
+	refreshTimeout     time.Duration
+	fetchFailures      map[string]*fetchFailure
+
+	flags      map[string]interface{}
+	refreshNow chan struct{}
+	stop       chan struct{}
+	stopOnce   sync.Once
+	disabledMu sync.RWMutex
+	disabled   map[string]bool
+
+	accessAllow []string
+	accessDeny  []string
+	access      atomic.Value
+
+	maintenanceMu         sync.RWMutex
+	maintenance           map[string]*maintenanceMode
+	maintenanceBody       string
+	maintenanceRetryAfter int
+
+	instanceID string
+	stateTopic string
+
+	breakerFailures int
+	breakerCooldown time.Duration
+	breakers        sync.Map
 }
 
 func New() *Handler {
 	return &Handler{
-		routes: make(map[string]*routerclientpb.RoutesReply_Route),
+		routes:      make(map[string]*proxyRoute),
+		registered:  make(map[string]bool),
+		reload:      make(chan struct{}, 1),
+		refreshNow:  make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+		disabled:    make(map[string]bool),
+		maintenance: make(map[string]*maintenanceMode),
+
+		fetchFailures: make(map[string]*fetchFailure),
+
+		rejectedRewrites: make(map[*rewriteRule]bool),
 	}
 }
 
-func (h *Handler) Init(r *components.Registry, engine *gin.Engine, refreshSeconds int, rlStoreURL string) error {
+// Init starts the refresh loop, newEngine creates a gin engine with the middlewares and the routes of the router
+func (h *Handler) Init(r *components.Registry, newEngine func() (*gin.Engine, error), c *cli.Context) error {
 	h.cReg = r
-	h.engine = engine
-	globalGroup := h.engine.Group("")
+	h.newEngine = newEngine
+	h.flags = effectiveFlags(c)
+	h.refreshSeconds = c.Int("router_refresh")
+	h.refreshConcurrency = c.Int("router_refresh_concurrency")
+	h.refreshTimeout = c.Duration("router_refresh_timeout")
+	h.configFile = c.String("router_config_file")
+	h.rewriteFlags = c.StringSlice("router_rewrite")
+	h.rlLegacyHeaders = c.Bool("router_ratelimit_legacy_headers")
+	h.breakerFailures = c.Int("router_breaker_failures")
+	h.breakerCooldown = c.Duration("router_breaker_cooldown")
+	h.maintenanceBody = c.String("router_maintenance_body")
+	h.maintenanceRetryAfter = c.Int("router_maintenance_retry_after")
+	if !json.Valid([]byte(h.maintenanceBody)) {
+		return fmt.Errorf("router_maintenance_body is not valid JSON")
+	}
 
+	// Admin changes get broadcast to the other instances
+	h.instanceID = h.cReg.Service().Server().Options().Id
+	h.stateTopic = c.String("router_state_topic")
+	if err := h.subscribeState(h.cReg.Service().Server()); err != nil {
+		return err
+	}
+
+	rlStoreURL := c.String("router_ratelimiter_store_url")
 	if strings.HasPrefix(rlStoreURL, "redis://") {
 		// Create a redis client.
 		option, err := libredis.ParseURL(rlStoreURL)
@@ -72,142 +211,142 @@ func (h *Handler) Init(r *components.Registry, engine *gin.Engine, refreshSecond
 		if err != nil {
 			return err
 		}
-		h.rlStore = store
+
+		// Keep limiting while redis is down
+		onError := c.String("router_ratelimiter_on_error")
+		buckets := &redisBucketStore{client: client, prefix: "rl:tb"}
+		h.rlFailover, err = newFailoverStore(store, buckets, onError, logruscomponent.MustReg(h.cReg).Logger())
+		if err != nil {
+			return err
+		}
+		h.rlStore = h.rlFailover
+		h.rlBuckets = failoverBuckets{h.rlFailover}
+		h.rlFailOpen = onError == ratelimitOnErrorOpen
+		h.rlFailClosed = onError == ratelimitOnErrorClosed
 	} else if rlStoreURL == "memory://" {
-		h.rlStore = memory.NewStore()
+		h.rlStore = newMemoryStore()
+		h.rlBuckets = newMemoryBucketStore()
+	}
+
+	if h.configFile != "" {
+		cfg, err := config.LoadFile(h.configFile)
+		if err != nil {
+			return err
+		}
+		h.config = cfg
+
+		if err := h.watchConfigFile(); err != nil {
+			return err
+		}
+	}
+
+	globalRewrites, err := parseGlobalRewrites(h.rewriteFlags, h.config)
+	if err != nil {
+		return err
+	}
+	h.globalRewrites = globalRewrites
+
+	h.rlPolicyFlags = map[string][]string{
+		"default_clientip": c.StringSlice("router_ratelimit_default_clientip"),
+		"default_user":     c.StringSlice("router_ratelimit_default_user"),
+		"ceiling_clientip": c.StringSlice("router_ratelimit_ceiling_clientip"),
+		"ceiling_user":     c.StringSlice("router_ratelimit_ceiling_user"),
+	}
+	rlPolicies, err := newRatelimitPolicies(h.rlPolicyFlags, h.config)
+	if err != nil {
+		return err
+	}
+	h.rlPolicies = rlPolicies
+
+	h.queueTimeout = c.Duration("router_queue_timeout")
+	if h.queueTimeout <= 0 {
+		return errors.New("router_queue_timeout must be positive")
+	}
+	h.concurrencyFlags, err = parseServiceConcurrency(c.StringSlice("router_service_concurrency"))
+	if err != nil {
+		return err
+	}
+	h.updateServiceConcurrency()
+
+	h.defaultBodyLimits = bodyLimits{
+		maxBodySize:          c.Int64("router_max_body_size"),
+		maxMultipartParts:    c.Int("router_max_multipart_parts"),
+		maxMultipartPartSize: c.Int64("router_max_multipart_part_size"),
+		maxJSONDepth:         c.Int("router_max_json_depth"),
+	}
+
+	// Hide the details of internal errors unless in debugmode
+	h.debugmode = c.Bool("router_debugmode")
+	h.errorFormatter, err = newErrorFormatter(c.String("router_error_format"), !c.Bool("router_debugmode"), c.StringSlice("router_error_status"))
+	if err != nil {
+		return err
+	}
+
+	if topic := c.String("router_audit_topic"); topic != "" {
+		h.auditPublisher = newAsyncPublisher(h.cReg.Service().Options().Broker, topic, c.Int("router_audit_buffer"), logruscomponent.MustReg(h.cReg).Logger())
+	}
+
+	h.accessAllow = c.StringSlice("router_allow_cidrs")
+	h.accessDeny = c.StringSlice("router_deny_cidrs")
+	access, err := newGlobalIPAccess(h.accessAllow, h.accessDeny, h.config)
+	if err != nil {
+		return err
 	}
+	h.access.Store(access)
 
-	// Refresh routes for the proxy every 10 seconds
+	engine, err := h.newEngine()
+	if err != nil {
+		return err
+	}
+	h.engine = engine
+	h.publish()
+
+	// Refresh routes for the proxy every refreshSeconds or when the config file changed
 	go func() {
 		logger := logruscomponent.MustReg(h.cReg).Logger()
 
 		for {
-			ctx := context.Background()
+			h.refresh(context.Background())
 
-			services, err := util.FindByEndpoint(h.cReg.Service(), "RouterClientService.Routes")
-			if err != nil {
-				logger.Error(err)
-				continue
-			}
+			// Probe the ratelimit store so it recovers without traffic
+			_ = h.Health(context.Background())
 
-			for _, s := range services {
-				logger.WithField("service", s.Name).Tracef("Found service")
-				client := routerclientpb.NewRouterClientService(s.Name, h.cReg.Service().Client())
-				sCtx, err := auth2.ClientAuthMustReg(h.cReg).Plugin().ServiceContext(ctx)
+			select {
+			case <-time.After(time.Duration(h.refreshSeconds) * time.Second):
+			case <-h.refreshNow:
+			case <-h.stop:
+				return
+			case <-h.reload:
+				cfg, err := config.LoadFile(h.configFile)
 				if err != nil {
-					logger.Error(err)
+					logger.WithField("file", h.configFile).Error(err)
 					continue
 				}
-				resp, err := client.Routes(sCtx, &emptypb.Empty{})
+				globalRewrites, err := parseGlobalRewrites(h.rewriteFlags, cfg)
 				if err != nil {
-					logger.Error(err)
-					// failure in getting routes, silently ignore
+					logger.WithField("file", h.configFile).Error(err)
 					continue
 				}
-
-				serviceGroup := globalGroup.Group(fmt.Sprintf("/%s", resp.GetRouterURI()))
-
-				for _, route := range resp.Routes {
-					var g *gin.RouterGroup = nil
-
-					if route.IsGlobal {
-						g = globalGroup
-					} else {
-						g = serviceGroup
-					}
-
-					// Calculate the pathMethod of the route and register it if it's not registered yet
-					pathMethod := fmt.Sprintf("%s:%s%s", route.Method, g.BasePath(), route.Path)
-					path := fmt.Sprintf("%s%s", g.BasePath(), route.Path)
-					if _, ok := h.routes[pathMethod]; !ok {
-						logger.
-							WithField("service", s.Name).
-							WithField("endpoint", route.Endpoint).
-							WithField("method", route.Method).
-							WithField("path", path).
-							WithField("ratelimitClientIP", route.RatelimitClientIP).
-							Debug("found route")
-
-						clientIPRatelimiter := make([]*limiter.Limiter, len(route.RatelimitClientIP))
-						if len(route.RatelimitClientIP) > 0 {
-							if h.rlStore == nil {
-								logger.
-									WithField("service", s.Name).
-									WithField("endpoint", route.Endpoint).
-									WithField("method", route.Method).
-									WithField("path", path).
-									WithField("ratelimitClientIP", route.RatelimitClientIP).
-									Error("found a route with a clientip limiter but there is no limiter store")
-								continue
-							}
-
-							haveError := false
-							for idx, rate := range route.RatelimitClientIP {
-								rate, err := limiter.NewRateFromFormatted(rate)
-								if err != nil {
-									logger.
-										WithField("service", s.Name).
-										WithField("endpoint", route.Endpoint).
-										WithField("method", route.Method).
-										WithField("path", path).
-										WithField("ratelimitClientIP", route.RatelimitClientIP).
-										Error(err)
-									haveError = true
-									break
-								}
-
-								clientIPRatelimiter[idx] = limiter.New(h.rlStore, rate)
-							}
-
-							if haveError {
-								continue
-							}
-						}
-
-						userRatelimiter := make([]*limiter.Limiter, len(route.RatelimitUser))
-						if route.AuthRequired && len(route.RatelimitUser) > 0 {
-							if h.rlStore == nil {
-								logger.
-									WithField("service", s.Name).
-									WithField("endpoint", route.Endpoint).
-									WithField("method", route.Method).
-									WithField("path", path).
-									WithField("ratelimitUser", route.RatelimitUser).
-									Error("found a route with a user limiter but there is no limiter store")
-								continue
-							}
-
-							haveError := false
-							for idx, rate := range route.RatelimitUser {
-								rate, err := limiter.NewRateFromFormatted(rate)
-								if err != nil {
-									logger.
-										WithField("service", s.Name).
-										WithField("endpoint", route.Endpoint).
-										WithField("method", route.Method).
-										WithField("path", path).
-										WithField("ratelimitUser", route.RatelimitUser).
-										Error(err)
-									haveError = true
-									break
-								}
-
-								userRatelimiter[idx] = limiter.New(h.rlStore, rate)
-							}
-
-							if haveError {
-								continue
-							}
-						}
-
-						g.Handle(route.Method, route.Path, h.proxy(s.Name, route, route.AuthRequired, path, clientIPRatelimiter, userRatelimiter))
-						h.routes[pathMethod] = route
-						h.routes[pathMethod].Path = path
-					}
+				rlPolicies, err := newRatelimitPolicies(h.rlPolicyFlags, cfg)
+				if err != nil {
+					logger.WithField("file", h.configFile).Error(err)
+					continue
 				}
-			}
+				access, err := newGlobalIPAccess(h.accessAllow, h.accessDeny, cfg)
+				if err != nil {
+					logger.WithField("file", h.configFile).Error(err)
+					continue
+				}
+				logger.WithField("file", h.configFile).Info("reloaded the config file")
+				h.config = cfg
+				h.globalRewrites = globalRewrites
+				h.rlPolicies = rlPolicies
+				h.updateServiceConcurrency()
+				h.access.Store(access)
 
-			time.Sleep(time.Duration(refreshSeconds) * time.Second)
+				// Register all routes again with the new config
+				h.generation++
+			}
 		}
 	}()
 
@@ -219,6 +358,65 @@ func (h *Handler) Init(r *components.Registry, engine *gin.Engine, refreshSecond
 			router.Endpoint(routerserverpb.RouterServerService.Routes),
 			router.RatelimitClientIP("1-S", "50-M", "1000-H"),
 		),
+		router.NewRoute(
+			router.Method(router.MethodGet),
+			router.Path("/v2/routes"),
+			router.Endpoint(routerserverpb.RouterServerService.RoutesV2),
+			router.Params("service", "pathPrefix", "pageSize", "pageToken"),
+			router.RatelimitClientIP("1-S", "50-M", "1000-H"),
+		),
+		router.NewRoute(
+			router.Method(router.MethodPost),
+			router.Path("/maintenance"),
+			router.Endpoint(routerserverpb.RouterServerService.Maintenance),
+			router.AuthRequired(),
+			router.RatelimitUser("10-M"),
+		),
+		router.NewRoute(
+			router.Method(router.MethodGet),
+			router.Path("/services"),
+			router.Endpoint(routerserverpb.RouterServerService.Services),
+			router.AuthRequired(),
+			router.RatelimitUser("60-M"),
+		),
+		router.NewRoute(
+			router.Method(router.MethodPost),
+			router.Path("/refresh"),
+			router.Endpoint(routerserverpb.RouterServerService.Refresh),
+			router.AuthRequired(),
+			router.RatelimitUser("10-M"),
+		),
+		router.NewRoute(
+			router.Method(router.MethodGet),
+			router.Path("/routes/state"),
+			router.Endpoint(routerserverpb.RouterServerService.RouteState),
+			router.Params("method", "path", "key"),
+			router.AuthRequired(),
+			router.RatelimitUser("60-M"),
+		),
+		router.NewRoute(
+			router.Method(router.MethodPost),
+			router.Path("/routes/enabled"),
+			router.Endpoint(routerserverpb.RouterServerService.SetRouteEnabled),
+			router.AuthRequired(),
+			router.RatelimitUser("10-M"),
+			router.Audit(),
+		),
+		router.NewRoute(
+			router.Method(router.MethodPost),
+			router.Path("/ratelimits/reset"),
+			router.Endpoint(routerserverpb.RouterServerService.ResetRatelimit),
+			router.AuthRequired(),
+			router.RatelimitUser("10-M"),
+			router.Audit(),
+		),
+		router.NewRoute(
+			router.Method(router.MethodGet),
+			router.Path("/config"),
+			router.Endpoint(routerserverpb.RouterServerService.Config),
+			router.AuthRequired(),
+			router.RatelimitUser("10-M"),
+		),
 	)
 
 	authVerifier := endpointroles.NewVerifier(
@@ -229,237 +427,524 @@ func (h *Handler) Init(r *components.Registry, engine *gin.Engine, refreshSecond
 			endpointroles.Endpoint(routerserverpb.RouterServerService.Routes),
 			endpointroles.RolesAllow(auth2.RolesServiceAndAdmin),
 		),
+		endpointroles.NewRule(
+			endpointroles.Endpoint(routerserverpb.RouterServerService.RoutesV2),
+			endpointroles.RolesAllow(auth2.RolesServiceAndAdmin),
+		),
+		endpointroles.NewRule(
+			endpointroles.Endpoint(routerserverpb.RouterServerService.Maintenance),
+			endpointroles.RolesAllow(auth2.RolesServiceAndAdmin),
+		),
 	)
+
+	// The admin API is for humans only
+	adminRoles := []string{auth2.ROLE_ADMIN, auth2.ROLE_SUPERADMIN}
+	for _, endpoint := range []interface{}{
+		routerserverpb.RouterServerService.Services,
+		routerserverpb.RouterServerService.Refresh,
+		routerserverpb.RouterServerService.RouteState,
+		routerserverpb.RouterServerService.SetRouteEnabled,
+		routerserverpb.RouterServerService.ResetRatelimit,
+		routerserverpb.RouterServerService.Config,
+	} {
+		authVerifier.AddRules(
+			endpointroles.NewRule(
+				endpointroles.Endpoint(endpoint),
+				endpointroles.RolesAllow(adminRoles),
+			),
+		)
+	}
 	auth2.ClientAuthMustReg(h.cReg).Plugin().AddVerifier(authVerifier)
 
 	routerserverpb.RegisterRouterServerServiceHandler(h.cReg.Service().Server(), h)
 
+	h.initialized.Store(true)
+	return nil
+}
+
+// refresh registers the static routes from the config file and asks all services for their routes
+func (h *Handler) refresh(ctx context.Context) {
+	logger := logruscomponent.MustReg(h.cReg).Logger()
+	defer h.publish()
+
+	if h.config != nil {
+		for _, route := range h.config.Routes {
+			h.registerRoute(route.Service, "", route.RouterURI, staticRoute(route), true)
+		}
+	}
+	h.removeStaleStaticRoutes()
+
+	services, err := util.FindByEndpoint(h.cReg.Service(), "RouterClientService.Routes")
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+	defer h.refreshed.Store(true)
+
+	// Ask the services in parallel but register their routes one after the other
+	for _, result := range h.fetchRoutes(ctx, services) {
+		if result.err != nil {
+			continue
+		}
+
+		for _, route := range result.resp.Routes {
+			h.registerRoute(result.service.Name, result.service.Version, result.resp.GetRouterURI(), route, false)
+		}
+	}
+}
+
+// registerRoute registers a route with gin if it's not registered yet, static routes replace routes from services,
+// the owner of a route replaces it when it sends a changed definition
+func (h *Handler) registerRoute(serviceName, version, routerURI string, route *routerclientpb.RoutesReply_Route, static bool) {
+	logger := logruscomponent.MustReg(h.cReg).Logger()
+	now := time.Now()
+
+	basePath := "/"
+	if !route.IsGlobal {
+		basePath = joinPaths(basePath, routerURI)
+	}
+
+	// Calculate the pathMethod of the route and register it if it's not registered yet
+	path := joinPaths(basePath, route.Path)
+	pathMethod := fmt.Sprintf("%s:%s", route.Method, path)
+	existing, ok := h.routes[pathMethod]
+	sameOwner := ok && existing.service == serviceName && existing.static == static
+	if sameOwner {
+		existing.lastSeen.Store(now.UnixNano())
+	}
+	if ok && existing.generation == h.generation && (existing.static || !static) &&
+		(!sameOwner || (existing.basePath == basePath && proto.Equal(existing.definition, route))) {
+		return
+	}
+
+	logger.
+		WithField("service", serviceName).
+		WithField("endpoint", route.Endpoint).
+		WithField("method", route.Method).
+		WithField("path", path).
+		WithField("ratelimitClientIP", route.RatelimitClientIP).
+		Debug("found route")
+
+	ratelimits, err := h.newRatelimits(serviceName, path, route)
+	if err != nil {
+		logger.
+			WithField("service", serviceName).
+			WithField("endpoint", route.Endpoint).
+			WithField("method", route.Method).
+			WithField("path", path).
+			WithField("ratelimitClientIP", route.RatelimitClientIP).
+			WithField("ratelimitUser", route.RatelimitUser).
+			WithField("ratelimits", route.Ratelimits).
+			Error(err)
+		return
+	}
+	if lacksClientIPLimit(ratelimits) {
+		logger.
+			WithField("service", serviceName).
+			WithField("method", route.Method).
+			WithField("path", path).
+			WithField("ratelimits", route.Ratelimits).
+			Warn("the route limits by values the client picks without a client IP limit, set a client IP ceiling")
+	}
+
+	var upstream *url.URL
+	if route.Type == router.TypeHTTP && route.Upstream != "" {
+		upstream, err = url.Parse(route.Upstream)
+		if err != nil {
+			logger.
+				WithField("service", serviceName).
+				WithField("method", route.Method).
+				WithField("path", path).
+				WithField("upstream", route.Upstream).
+				Error(err)
+			return
+		}
+	}
+
+	access, err := newIPAccess(route.AllowCIDRs, route.DenyCIDRs)
+	if err != nil {
+		logger.
+			WithField("service", serviceName).
+			WithField("method", route.Method).
+			WithField("path", path).
+			WithField("allowCIDRs", route.AllowCIDRs).
+			WithField("denyCIDRs", route.DenyCIDRs).
+			Error(err)
+		return
+	}
+
+	if err := validTypedParams(route.TypedParams, route.ArrayStyle); err != nil {
+		logger.
+			WithField("service", serviceName).
+			WithField("endpoint", route.Endpoint).
+			WithField("method", route.Method).
+			WithField("path", path).
+			Error(err)
+		return
+	}
+
+	schema, err := compileSchema(route.Schema)
+	if err != nil {
+		logger.
+			WithField("service", serviceName).
+			WithField("endpoint", route.Endpoint).
+			WithField("method", route.Method).
+			WithField("path", path).
+			Error(err)
+		return
+	}
+
+	rewrites, err := routeRewrites(route.Method, path, basePath, static, route.Rewrites)
+	if err != nil {
+		logger.
+			WithField("service", serviceName).
+			WithField("method", route.Method).
+			WithField("path", path).
+			Error(err)
+		return
+	}
+
+	// gin doesn't allow to register a route twice, the handler looks up the route on every request
+	if !h.registered[pathMethod] {
+		if err := h.handle(route.Method, path, h.dispatch(pathMethod)); err != nil {
+			logger.
+				WithField("service", serviceName).
+				WithField("method", route.Method).
+				WithField("path", path).
+				Error(err)
+			return
+		}
+		h.registered[pathMethod] = true
+	}
+
+	registeredAt := now
+	if sameOwner {
+		registeredAt = existing.registeredAt
+	}
+
+	definition := proto.Clone(route).(*routerclientpb.RoutesReply_Route)
+	route.Path = path
+	pr := &proxyRoute{
+		service:     serviceName,
+		basePath:    basePath,
+		route:       route,
+		definition:  definition,
+		static:      static,
+		upstream:    upstream,
+		rewrites:    rewrites,
+		ratelimits:  ratelimits,
+		concurrency: routeConcurrencyLimit(existing, route, h.queueTimeout),
+		access:      access,
+		schema:      schema,
+		generation:  h.generation,
+
+		version:      version,
+		registeredAt: registeredAt,
+	}
+	pr.lastSeen.Store(now.UnixNano())
+	h.routes[pathMethod] = pr
+}
+
+// handle registers a route with gin, it returns the panic of gin as error when the path conflicts with another route
+func (h *Handler) handle(method, path string, handler gin.HandlerFunc) error {
+	// gin's routing trees can't change while it serves, add the route to a new engine that publish swaps in
+	if h.nextEngine == nil {
+		engine, err := h.newEngine()
+		if err != nil {
+			return err
+		}
+
+		pathMethods := make([]string, 0, len(h.registered))
+		for pathMethod := range h.registered {
+			pathMethods = append(pathMethods, pathMethod)
+		}
+		sort.Strings(pathMethods)
+		for _, pathMethod := range pathMethods {
+			method, path, _ := strings.Cut(pathMethod, ":")
+			engine.Handle(method, path, h.dispatch(pathMethod))
+		}
+
+		h.nextEngine = engine
+	}
+
+	return handleRecover(h.nextEngine, method, path, handler)
+}
+
+func handleRecover(engine *gin.Engine, method, path string, handler gin.HandlerFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	engine.Handle(method, path, handler)
+	return nil
+}
+
+// publish makes the routes of the refresh visible, requests get a copy as the refresh goroutine keeps changing h.routes
+func (h *Handler) publish() {
+	if h.nextEngine != nil {
+		h.engine = h.nextEngine
+		h.nextEngine = nil
+	}
+
+	routes := make(map[string]*proxyRoute, len(h.routes))
+	for pathMethod, pr := range h.routes {
+		routes[pathMethod] = pr
+	}
+
+	h.table.Store(&routeTable{routes: routes, engine: h.engine, config: h.config})
+	h.updateRewrites()
+}
+
+// loadTable returns the routes of the last refresh
+func (h *Handler) loadTable() *routeTable {
+	if table, ok := h.table.Load().(*routeTable); ok {
+		return table
+	}
+	return &routeTable{}
+}
+
+// ServeHTTP serves a request with the gin engine of the last refresh
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	table := h.loadTable()
+	if table.engine == nil {
+		http.Error(w, "the router is starting", http.StatusServiceUnavailable)
+		return
+	}
+
+	table.engine.ServeHTTP(w, r)
+}
+
+// Health reports the state of the ratelimit store
+func (h *Handler) Health(ctx context.Context) error {
+	if h.rlFailover != nil {
+		return h.rlFailover.Health(ctx)
+	}
+
 	return nil
 }
 
+// Stop ends the refresh loop, stops watching the config file and publishes the queued audit events
 func (h *Handler) Stop() error {
+	h.stopOnce.Do(func() {
+		close(h.stop)
+
+		if h.configWatcher != nil {
+			signal.Stop(h.configSignals)
+			h.configWatcher.Close()
+		}
+	})
+
+	if h.auditPublisher != nil {
+		return h.auditPublisher.Close()
+	}
+
 	return nil
 }
 
-func (h *Handler) proxy(serviceName string, route *routerclientpb.RoutesReply_Route, authRequired bool, path string, clientIPRatelimiter []*limiter.Limiter, userRatelimiter []*limiter.Limiter) func(*gin.Context) {
-	return func(c *gin.Context) {
+// joinPaths joins two URL paths like gin does, it keeps a trailing slash
+func joinPaths(absolutePath, relativePath string) string {
+	if relativePath == "" {
+		return absolutePath
+	}
 
-		if len(clientIPRatelimiter) > 0 {
-			for _, l := range clientIPRatelimiter {
-				context, err := l.Get(c, fmt.Sprintf("%s-%s-%s", path, l.Rate.Formatted, c.ClientIP()))
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{
-						"errors": []gin.H{
-							{
-								"id":      "INTERNAL_SERVER_ERROR",
-								"message": err,
-							},
-						},
-					})
-					c.Abort()
-					return
-				}
+	finalPath := gopath.Join(absolutePath, relativePath)
+	if strings.HasSuffix(relativePath, "/") && !strings.HasSuffix(finalPath, "/") {
+		return finalPath + "/"
+	}
+	return finalPath
+}
 
-				c.Header("X-ClientIPRateLimit-Limit", strconv.FormatInt(context.Limit, 10))
-				c.Header("X-ClientIPRateLimit-Remaining", strconv.FormatInt(context.Remaining, 10))
-				c.Header("X-ClientIPRateLimit-Reset", strconv.FormatInt(context.Reset, 10))
-
-				if context.Reached {
-					c.JSON(http.StatusTooManyRequests, gin.H{
-						"errors": []gin.H{
-							{
-								"id":      "TO_MANY_REQUESTS",
-								"message": "To many requests",
-							},
-						},
-					})
-					c.Abort()
-					return
-				}
-			}
+// dispatch returns the gin handler for pathMethod, routes removed from the config file answer with NOT_FOUND
+func (h *Handler) dispatch(pathMethod string) func(*gin.Context) {
+	return func(c *gin.Context) {
+		c.Set(errorFormatterContextKey, h.errorFormatter)
+
+		pr, ok := h.loadTable().routes[pathMethod]
+		if !ok {
+			abortWithError(c, http.StatusNotFound, "NOT_FOUND", "page not found")
+			return
 		}
+		c.Set(proxyRouteContextKey, pr)
 
-		// Map query/path params
-		params := make(map[string]string)
-		for _, p := range route.Params {
-			if len(c.Query(p)) > 0 {
-				params[p] = c.Query(p)
-			}
+		if h.routeDisabled(pathMethod) {
+			abortWithError(c, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "route disabled")
+			return
 		}
-		for _, p := range route.Params {
-			if len(c.Param(p)) > 0 {
-				params[p] = c.Param(p)
-			}
+
+		if pr.route.Audit {
+			defer h.audit(c, pr, time.Now())
 		}
 
-		// Bind the request if POST/PATCH/PUT
-		request := gin.H{}
-		if c.Request.Method == http.MethodPost || c.Request.Method == http.MethodPatch || c.Request.Method == http.MethodPut {
-			mf, err := c.MultipartForm()
-			if err == nil {
-				for k, files := range mf.File {
-					for _, file := range files {
-						fp, err := file.Open()
-						if err != nil {
-							continue
-						}
-						data, err := io.ReadAll(fp)
-						if err != nil {
-							continue
-						}
-
-						if len(files) > 1 {
-							if _, ok := request[k]; !ok {
-								request[k] = []string{base64.StdEncoding.EncodeToString(data)}
-							} else {
-								request[k] = append(request[k].([]string), base64.StdEncoding.EncodeToString(data))
-							}
-						} else {
-							request[k] = base64.StdEncoding.EncodeToString(data)
-						}
-					}
-				}
+		h.proxy(c, pr)
+	}
+}
 
-				for k, v := range mf.Value {
-					if len(v) > 1 {
-						request[k] = v
-					} else {
-						request[k] = v[0]
-					}
+func (h *Handler) proxy(c *gin.Context, pr *proxyRoute) {
+	route := pr.route
 
-				}
-			} else {
-				if c.ContentType() == "" {
-					c.JSON(http.StatusUnsupportedMediaType, gin.H{
-						"errors": []gin.H{
-							{
-								"id":      "UNSUPPORTED_MEDIA_TYPE",
-								"message": "provide a content-type header",
-							},
-						},
-					})
-					c.Abort()
-					return
-				}
-				c.ShouldBind(&request)
-			}
-		}
+	if route.GrpcWeb && isGRPC(c) {
+		c.Set(grpcContextKey, true)
+	}
+
+	if !h.checkAccess(c, pr) {
+		return
+	}
+
+	if m := h.maintenanceFor(pr.service); m != nil {
+		h.abortWithMaintenance(c, m)
+		return
+	}
 
-		// Set query/route params to the request
-		for pn, p := range params {
-			request[pn] = p
+	switch route.Type {
+	case router.TypeRedirect:
+		redirect(c, pr)
+		return
+	case router.TypeMaintenance:
+		h.abortWithMaintenance(c, nil)
+		return
+	}
+
+	if !h.limitBody(c, pr) {
+		return
+	}
+
+	if !h.checkRatelimits(c, pr, nil) {
+		return
+	}
+
+	// Auth
+	u, authErr := auth2.RouterAuthMustReg(h.cReg).Plugin().Inspect(c.Request)
+	var (
+		ctx context.Context
+		err error
+	)
+	if authErr != nil && route.AuthRequired {
+		abortWithError(c, http.StatusUnauthorized, "UNAUTHORIZED", "authentication required")
+		return
+	} else if authErr != nil {
+		ctx, err = auth2.RouterAuthMustReg(h.cReg).Plugin().ForwardContext(auth2.AnonUser, c.Request, c)
+		if err != nil {
+			logger.Error(err)
+			abortWithError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", err)
+			return
+		}
+	} else {
+		c.Set(userContextKey, u)
+		ctx, err = auth2.RouterAuthMustReg(h.cReg).Plugin().ForwardContext(u, c.Request, c)
+		if err != nil {
+			logger.Error(err)
+			abortWithError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", err)
+			return
 		}
+	}
 
-		req := h.cReg.Service().Client().NewRequest(serviceName, route.Endpoint, request, client.WithContentType("application/json"))
+	if authErr == nil && !h.checkRatelimits(c, pr, u) {
+		return
+	}
 
-		// Auth
-		u, authErr := auth2.RouterAuthMustReg(h.cReg).Plugin().Inspect(c.Request)
-		var (
-			ctx context.Context
-			err error
-		)
-		if authErr != nil && authRequired {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"errors": []gin.H{
-					{
-						"id":      "UNAUTHORIZED",
-						"message": err,
-					},
-				},
-			})
-			c.Abort()
+	release, ok := h.acquireConcurrency(c, pr)
+	if !ok {
+		return
+	}
+	defer release()
+
+	record, ok := h.checkBreaker(c, pr)
+	if !ok {
+		return
+	}
+	defer record()
+
+	switch route.Type {
+	case router.TypeHTTP:
+		h.proxyHTTP(ctx, c, pr)
+	default:
+		if c.GetBool(grpcContextKey) {
+			h.callGRPC(ctx, c, pr)
 			return
-		} else if authErr != nil {
-			ctx, err = auth2.RouterAuthMustReg(h.cReg).Plugin().ForwardContext(auth2.AnonUser, c.Request, c)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"errors": []gin.H{
-						{
-							"id":      "INTERNAL_SERVER_ERROR",
-							"message": err,
-						},
-					},
-				})
+		}
+		h.callRPC(ctx, c, pr)
+	}
+}
+
+// callRPC binds the request and calls the endpoint with the go-micro client
+func (h *Handler) callRPC(ctx context.Context, c *gin.Context, pr *proxyRoute) {
+	route := pr.route
+
+	// Map query/path params
+	params, errs := typedParams(c, route)
+	if len(errs) > 0 {
+		abortWithFieldErrors(c, errs)
+		return
+	}
+	params = withUntypedParams(c, route, params)
+
+	// Bind the request if POST/PATCH/PUT
+	request := gin.H{}
+	if c.Request.Method == http.MethodPost || c.Request.Method == http.MethodPatch || c.Request.Method == http.MethodPut {
+		limits := h.bodyLimits(route)
+		mr, err := c.Request.MultipartReader()
+		if err == nil {
+			if err := readMultipart(mr, limits, request); err != nil {
+				abortWithBodyError(c, err)
+				return
 			}
 		} else {
-			ctx, err = auth2.RouterAuthMustReg(h.cReg).Plugin().ForwardContext(u, c.Request, c)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"errors": []gin.H{
-						{
-							"id":      "INTERNAL_SERVER_ERROR",
-							"message": err,
-						},
-					},
-				})
+			if c.ContentType() == "" {
+				abortWithError(c, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE", "provide a content-type header")
+				return
 			}
-		}
 
-		if authErr == nil && len(userRatelimiter) > 0 {
-			for _, l := range userRatelimiter {
-				context, err := l.Get(c, fmt.Sprintf("%s-%s-%s", path, l.Rate.Formatted, u.Id))
+			if c.ContentType() == binding.MIMEJSON {
+				body, err := io.ReadAll(c.Request.Body)
 				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{
-						"errors": []gin.H{
-							{
-								"id":      "INTERNAL_SERVER_ERROR",
-								"message": err,
-							},
-						},
-					})
-					c.Abort()
+					abortWithBodyError(c, err)
 					return
 				}
-
-				c.Header("X-UserRateLimit-Limit", strconv.FormatInt(context.Limit, 10))
-				c.Header("X-UserRateLimit-Remaining", strconv.FormatInt(context.Remaining, 10))
-				c.Header("X-UserRateLimit-Reset", strconv.FormatInt(context.Reset, 10))
-
-				if context.Reached {
-					c.JSON(http.StatusTooManyRequests, gin.H{
-						"errors": []gin.H{
-							{
-								"id":      "TO_MANY_REQUESTS",
-								"message": "To many requests",
-							},
-						},
-					})
-					c.Abort()
+				if err := checkJSONDepth(body, limits.maxJSONDepth); err != nil {
+					abortWithError(c, http.StatusBadRequest, "BAD_REQUEST", err)
 					return
 				}
+				c.Request.Body = io.NopCloser(bytes.NewReader(body))
 			}
-		}
 
-		// remote call
-		var response json.RawMessage
-		err = h.cReg.Service().Client().Call(ctx, req, &response)
-		if err != nil {
-			logger.Error(err)
-
-			pErr := errors.FromError(err)
-			code := int(http.StatusInternalServerError)
-			id := pErr.Id
-			if pErr.Code != 0 {
-				code = int(pErr.Code)
+			var maxBytesErr *http.MaxBytesError
+			if err := c.ShouldBind(&request); errors.As(err, &maxBytesErr) {
+				abortWithBodyError(c, err)
+				return
 			}
-			c.JSON(code, gin.H{
-				"errors": []gin.H{
-					{
-						"id":      id,
-						"message": pErr.Detail,
-					},
-				},
-			})
-			c.Abort()
-			return
 		}
+	}
+
+	// Set query/route params to the request
+	for pn, p := range params {
+		request[pn] = p
+	}
 
-		c.JSON(http.StatusOK, response)
+	if !validateRequest(c, pr, request) {
+		return
 	}
+
+	req := h.cReg.Service().Client().NewRequest(pr.service, route.Endpoint, request, client.WithContentType("application/json"))
+
+	// remote call
+	var response json.RawMessage
+	err := h.cReg.Service().Client().Call(ctx, req, &response)
+	if err != nil {
+		logger.Error(err)
+
+		code, id, detail := upstreamError(c, err)
+		abortWithError(c, code, id, detail)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 func (h *Handler) Routes(ctx context.Context, in *emptypb.Empty, out *routerserverpb.RoutesReply) error {
-	for _, route := range h.routes {
+	for _, pr := range h.loadTable().routes {
+		route := pr.route
 		out.Routes = append(out.Routes, &routerserverpb.RoutesReply_Route{
 			Method:            route.Method,
 			Path:              route.Path,
@@ -468,8 +953,83 @@ func (h *Handler) Routes(ctx context.Context, in *emptypb.Empty, out *routerserv
 			AuthRequired:      route.AuthRequired,
 			RatelimitClientIP: route.RatelimitClientIP,
 			ReatelimitUser:    route.RatelimitUser,
+			RatelimitUser:     route.RatelimitUser,
 		})
 	}
 
 	return nil
 }
+
+// RoutesV2 returns the routes with their owner, sorted by path and method, a page at a time
+func (h *Handler) RoutesV2(ctx context.Context, in *routerserverpb.RoutesV2Request, out *routerserverpb.RoutesV2Reply) error {
+	pageSize := int(in.PageSize)
+	if pageSize <= 0 {
+		pageSize = 100
+	} else if pageSize > 1000 {
+		pageSize = 1000
+	}
+
+	routes := h.loadTable().routes
+	keys := []string{}
+	for pathMethod, pr := range routes {
+		if in.Service != "" && pr.service != in.Service {
+			continue
+		}
+		if !strings.HasPrefix(pr.route.Path, in.PathPrefix) {
+			continue
+		}
+		keys = append(keys, pathMethod)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := routes[keys[i]].route, routes[keys[j]].route
+		if a.Path == b.Path {
+			return a.Method < b.Method
+		}
+		return a.Path < b.Path
+	})
+
+	out.Version = 2
+	out.TotalSize = int32(len(keys))
+
+	// The page token is the last route of the previous page
+	start := 0
+	if in.PageToken != "" {
+		start = sort.Search(len(keys), func(i int) bool {
+			route := routes[keys[i]].route
+			method, path, _ := strings.Cut(in.PageToken, ":")
+			return route.Path > path || (route.Path == path && route.Method > method)
+		})
+	}
+
+	end := start + pageSize
+	if end > len(keys) {
+		end = len(keys)
+	}
+
+	for _, pathMethod := range keys[start:end] {
+		pr := routes[pathMethod]
+		route := pr.route
+		out.Routes = append(out.Routes, &routerserverpb.RoutesV2Reply_Route{
+			Method:            route.Method,
+			Path:              route.Path,
+			Service:           pr.service,
+			Version:           pr.version,
+			IsGlobal:          route.IsGlobal,
+			Type:              route.Type,
+			Endpoint:          route.Endpoint,
+			Params:            route.Params,
+			AuthRequired:      route.AuthRequired,
+			RatelimitClientIP: route.RatelimitClientIP,
+			RatelimitUser:     route.RatelimitUser,
+			Static:            pr.static,
+			RegisteredAt:      timestamppb.New(pr.registeredAt),
+			LastSeenAt:        timestamppb.New(time.Unix(0, pr.lastSeen.Load())),
+		})
+	}
+
+	if end < len(keys) {
+		out.NextPageToken = keys[end-1]
+	}
+
+	return nil
+}
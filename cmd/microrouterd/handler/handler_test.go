@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/urfave/cli/v2"
+	"go-micro.dev/v4"
+	"go-micro.dev/v4/registry"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"jochum.dev/jo-micro/components"
+	"jochum.dev/jo-micro/logruscomponent"
+	"jochum.dev/jo-micro/router/cmd/microrouterd/config"
+	"jochum.dev/jo-micro/router/internal/proto/routerclientpb"
+	"jochum.dev/jo-micro/router/internal/proto/routerserverpb"
+)
+
+// newTestHandler returns a handler with an empty registry, it's set up like Init does without starting the refresh loop
+func newTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	service := micro.NewService(micro.Registry(registry.NewMemoryRegistry()))
+	cReg := components.New(service, "router", logruscomponent.New())
+
+	app := cli.NewApp()
+	app.Flags = cReg.AppendFlags([]cli.Flag{})
+	app.Action = func(c *cli.Context) error {
+		return cReg.Init(c)
+	}
+	if err := app.Run([]string{"test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	h := New()
+	h.cReg = cReg
+
+	var err error
+	h.errorFormatter, err = newErrorFormatter(errorFormatEnvelope, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.newEngine = func() (*gin.Engine, error) {
+		r := gin.New()
+		r.NoRoute(h.NoRoute)
+		r.GET("/healthz", h.Healthz)
+		return r, nil
+	}
+	if h.engine, err = h.newEngine(); err != nil {
+		t.Fatal(err)
+	}
+	h.publish()
+
+	return h
+}
+
+func TestRegisterRouteChangedDefinition(t *testing.T) {
+	h := newTestHandler(t)
+
+	route := func(endpoint string) *routerclientpb.RoutesReply_Route {
+		return &routerclientpb.RoutesReply_Route{Method: "GET", Path: "/users", Endpoint: endpoint}
+	}
+
+	h.registerRoute("users", "1", "/api", route("Users.List"), false)
+	first := h.routes["GET:/api/users"]
+	if first == nil {
+		t.Fatal("route not registered")
+	}
+
+	h.registerRoute("users", "1", "/api", route("Users.List"), false)
+	if h.routes["GET:/api/users"] != first {
+		t.Error("an unchanged route got registered again")
+	}
+
+	h.registerRoute("users", "1", "/api", route("Users.Search"), false)
+	if got := h.routes["GET:/api/users"].route.Endpoint; got != "Users.Search" {
+		t.Errorf("got endpoint %s, want Users.Search", got)
+	}
+
+	// Other services can't take over a route
+	h.registerRoute("other", "1", "/api", route("Other.List"), false)
+	if got := h.routes["GET:/api/users"].service; got != "users" {
+		t.Errorf("got owner %s, want users", got)
+	}
+}
+
+// TestRouteTableRace refreshes with changing routes while requests and RPCs read the route table,
+// run it with -race
+func TestRouteTableRace(t *testing.T) {
+	h := newTestHandler(t)
+
+	configs := []*config.File{
+		{Routes: []config.Route{
+			{Service: "legacy", Type: "redirect", Method: "GET", Path: "/old", Redirect: "/new"},
+			{Service: "legacy", Type: "maintenance", Method: "GET", Path: "/down"},
+			{Service: "legacy", Type: "maintenance", Method: "GET", Path: "/extra"},
+		}},
+		{Routes: []config.Route{
+			{Service: "legacy", Type: "maintenance", Method: "GET", Path: "/old"},
+			{Service: "legacy", Type: "redirect", Method: "GET", Path: "/down", Redirect: "/up", RedirectCode: 301},
+		}},
+	}
+	h.config = configs[0]
+	h.refresh(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+
+	// The refresh goroutine, like a config file reload
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		for i := 1; i <= 200; i++ {
+			h.config = configs[i%len(configs)]
+			h.generation++
+			h.refresh(context.Background())
+		}
+	}()
+
+	readers := []func(){
+		func() {
+			for _, path := range []string{"/old", "/down", "/extra", "/healthz", "/missing"} {
+				w := httptest.NewRecorder()
+				h.RewriteHandler(h).ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+				if w.Code == http.StatusOK && path != "/healthz" {
+					t.Errorf("%s: got status 200", path)
+				}
+			}
+		},
+		func() {
+			if err := h.Routes(context.Background(), &emptypb.Empty{}, &routerserverpb.RoutesReply{}); err != nil {
+				t.Error(err)
+			}
+		},
+		func() {
+			if err := h.Services(context.Background(), &emptypb.Empty{}, &routerserverpb.ServicesReply{}); err != nil {
+				t.Error(err)
+			}
+		},
+		func() {
+			if err := h.RouteState(context.Background(), &routerserverpb.RouteStateRequest{Method: "GET", Path: "/old"}, &routerserverpb.RouteStateReply{}); err != nil {
+				t.Error(err)
+			}
+		},
+		func() {
+			in := &routerserverpb.SetRouteEnabledRequest{Method: "GET", Path: "/down"}
+			if err := h.SetRouteEnabled(context.Background(), in, &routerserverpb.RouteStateReply{}); err != nil {
+				t.Error(err)
+			}
+		},
+		func() {
+			if err := h.Config(context.Background(), &emptypb.Empty{}, &routerserverpb.ConfigReply{}); err != nil {
+				t.Error(err)
+			}
+		},
+	}
+	for _, read := range readers {
+		wg.Add(1)
+		go func(read func()) {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				read()
+			}
+		}(read)
+	}
+
+	wg.Wait()
+}
+
+func TestRoutesV2(t *testing.T) {
+	routes := map[string]*proxyRoute{}
+	for _, r := range []struct{ service, method, path string }{
+		{"users", "GET", "/api/users"},
+		{"users", "POST", "/api/users"},
+		{"users", "GET", "/api/users/:id"},
+		{"reports", "GET", "/api/reports"},
+		{"reports", "GET", "/health"},
+	} {
+		routes[r.method+":"+r.path] = &proxyRoute{service: r.service, route: &routerclientpb.RoutesReply_Route{Method: r.method, Path: r.path}}
+	}
+	h := &Handler{}
+	h.table.Store(&routeTable{routes: routes})
+
+	// page collects all pages and returns the routes as "METHOD path"
+	page := func(in *routerserverpb.RoutesV2Request) ([]string, int) {
+		got := []string{}
+		pages := 0
+		for {
+			out := &routerserverpb.RoutesV2Reply{}
+			if err := h.RoutesV2(context.Background(), in, out); err != nil {
+				t.Fatal(err)
+			}
+			pages++
+			for _, r := range out.Routes {
+				got = append(got, r.Method+" "+r.Path)
+			}
+			if out.NextPageToken == "" {
+				return got, pages
+			}
+			in.PageToken = out.NextPageToken
+		}
+	}
+
+	got, pages := page(&routerserverpb.RoutesV2Request{PageSize: 2})
+	want := []string{"GET /api/reports", "GET /api/users", "POST /api/users", "GET /api/users/:id", "GET /health"}
+	if !reflect.DeepEqual(got, want) || pages != 3 {
+		t.Errorf("got %v in %d pages, want %v in 3", got, pages, want)
+	}
+
+	got, _ = page(&routerserverpb.RoutesV2Request{Service: "users", PathPrefix: "/api/users/"})
+	if want := []string{"GET /api/users/:id"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("filtered: got %v, want %v", got, want)
+	}
+
+	// A page token whose route is gone continues after it
+	out := &routerserverpb.RoutesV2Reply{}
+	if err := h.RoutesV2(context.Background(), &routerserverpb.RoutesV2Request{PageSize: 1, PageToken: "PUT:/api/users"}, out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Routes) != 1 || out.Routes[0].Path != "/api/users/:id" || out.TotalSize != 5 {
+		t.Errorf("got %v of %d", out.Routes, out.TotalSize)
+	}
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go-micro.dev/v4/registry"
+	"jochum.dev/jo-micro/auth2"
+	"jochum.dev/jo-micro/logruscomponent"
+)
+
+// healthCheckTimeout is how long a single readiness check may take
+const healthCheckTimeout = 2 * time.Second
+
+// degradedError is returned by checks whose failure the router works around, it doesn't fail /readyz
+type degradedError struct {
+	err error
+}
+
+func (e *degradedError) Error() string {
+	return e.err.Error()
+}
+
+// healthCheck is one check of /readyz, it returns nil if it's ok
+type healthCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// Healthz answers liveness probes, the process is alive as long as it serves requests
+func (h *Handler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// SetDraining makes /readyz fail from now on, the server calls it when it starts to shut down
+func (h *Handler) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Readyz answers readiness probes with the status of every check, 503 if one failed or the router shuts down,
+// the errors of the checks are logged and only shown in debugmode
+func (h *Handler) Readyz(c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "fail", "checks": gin.H{"handler": gin.H{"status": "fail", "error": "draining"}}})
+		return
+	}
+
+	if !h.initialized.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "fail", "checks": gin.H{"handler": gin.H{"status": "fail", "error": "not initialized"}}})
+		return
+	}
+
+	checks := []healthCheck{
+		{"refresh", h.checkRefreshed},
+		{"registry", h.checkRegistry},
+		{"ratelimiter", h.checkRatelimiter},
+		{"auth", h.checkAuth},
+	}
+
+	status, result := h.runReadyChecks(c.Request.Context(), checks)
+	c.JSON(status, result)
+}
+
+// runReadyChecks runs the checks and returns the HTTP status and the answer of /readyz
+func (h *Handler) runReadyChecks(ctx context.Context, checks []healthCheck) (int, gin.H) {
+	logger := logruscomponent.MustReg(h.cReg).Logger()
+	status := http.StatusOK
+	result := "ok"
+	results := gin.H{}
+	for _, hc := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := runHealthCheck(checkCtx, hc.check)
+		cancel()
+
+		if err == nil {
+			results[hc.name] = gin.H{"status": "ok"}
+			continue
+		}
+
+		checkStatus := "fail"
+		var degraded *degradedError
+		if errors.As(err, &degraded) {
+			checkStatus = "degraded"
+			if status == http.StatusOK {
+				result = "degraded"
+			}
+		} else {
+			status = http.StatusServiceUnavailable
+			result = "fail"
+		}
+		logger.WithField("check", hc.name).WithField("status", checkStatus).WithField("error", err).Warn("readiness check failed")
+
+		// The errors may contain internal addresses, /readyz is public
+		checkResult := gin.H{"status": checkStatus}
+		if h.debugmode {
+			checkResult["error"] = err.Error()
+		}
+		results[hc.name] = checkResult
+	}
+
+	return status, gin.H{"status": result, "checks": results}
+}
+
+// runHealthCheck runs check and gives up when ctx is done, for checks that don't watch ctx
+func runHealthCheck(ctx context.Context, check func(context.Context) error) error {
+	result := make(chan error, 1)
+	go func() {
+		result <- check(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *Handler) checkRefreshed(ctx context.Context) error {
+	if !h.refreshed.Load() {
+		return errors.New("routes haven't been refreshed yet")
+	}
+	return nil
+}
+
+// checkRatelimiter fails only if requests fail while the ratelimit store is down, else the router is degraded
+func (h *Handler) checkRatelimiter(ctx context.Context) error {
+	err := h.Health(ctx)
+	if err != nil && !h.rlFailClosed {
+		return &degradedError{err}
+	}
+	return err
+}
+
+func (h *Handler) checkRegistry(ctx context.Context) error {
+	_, err := h.cReg.Service().Options().Registry.ListServices(registry.ListContext(ctx))
+	return err
+}
+
+func (h *Handler) checkAuth(ctx context.Context) error {
+	reg := auth2.RouterAuthMustReg(h.cReg)
+	if !reg.Initialized() {
+		return errors.New("auth2 is not initialized")
+	}
+	return reg.Health(ctx)
+}
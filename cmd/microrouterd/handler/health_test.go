@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	limiter "github.com/ulule/limiter/v3"
+)
+
+// downStore is a ratelimit store that's never reachable
+type downStore struct{}
+
+var errDown = errors.New("connection refused")
+
+func (downStore) Get(context.Context, string, limiter.Rate) (limiter.Context, error) {
+	return limiter.Context{}, errDown
+}
+
+func (downStore) Peek(context.Context, string, limiter.Rate) (limiter.Context, error) {
+	return limiter.Context{}, errDown
+}
+
+func (downStore) Reset(context.Context, string, limiter.Rate) (limiter.Context, error) {
+	return limiter.Context{}, errDown
+}
+
+func (downStore) Increment(context.Context, string, int64, limiter.Rate) (limiter.Context, error) {
+	return limiter.Context{}, errDown
+}
+
+func TestCheckRatelimiter(t *testing.T) {
+	tests := []struct {
+		onError  string
+		store    limiter.Store
+		degraded bool
+		fail     bool
+	}{
+		{onError: ratelimitOnErrorMemory, store: newMemoryStore()},
+		{onError: ratelimitOnErrorMemory, store: downStore{}, degraded: true},
+		{onError: ratelimitOnErrorOpen, store: downStore{}, degraded: true},
+		{onError: ratelimitOnErrorClosed, store: downStore{}, fail: true},
+	}
+
+	for _, tt := range tests {
+		logger := logrus.New()
+		logger.SetLevel(logrus.PanicLevel)
+		s, err := newFailoverStore(tt.store, newMemoryBucketStore(), tt.onError, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := &Handler{rlFailover: s, rlFailClosed: tt.onError == ratelimitOnErrorClosed}
+
+		// The first call notices that the store is down
+		_, _ = s.Get(context.Background(), "key", limiter.Rate{Period: 1, Limit: 1})
+
+		err = h.checkRatelimiter(context.Background())
+		var degraded *degradedError
+		if got := errors.As(err, &degraded); got != tt.degraded {
+			t.Errorf("%s: got degraded %v, want %v", tt.onError, got, tt.degraded)
+		}
+		if got := err != nil && !tt.degraded; got != tt.fail {
+			t.Errorf("%s: got fail %v (%v), want %v", tt.onError, got, err, tt.fail)
+		}
+	}
+}
+
+func TestReadyzDraining(t *testing.T) {
+	h := newTestHandler(t)
+	h.initialized.Store(true)
+	h.SetDraining()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	h.Readyz(c)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("got %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestRunReadyChecks(t *testing.T) {
+	checks := []healthCheck{
+		{"ok", func(context.Context) error { return nil }},
+		{"degraded", func(context.Context) error { return &degradedError{errors.New("redis at 10.0.0.5:6379 down")} }},
+		{"fail", func(context.Context) error { return errors.New("dial tcp 10.0.0.6:8500: connection refused") }},
+	}
+
+	for _, debugmode := range []bool{false, true} {
+		h := newTestHandler(t)
+		h.debugmode = debugmode
+
+		status, result := h.runReadyChecks(context.Background(), checks)
+		if status != http.StatusServiceUnavailable || result["status"] != "fail" {
+			t.Errorf("debugmode %v: got %d %v", debugmode, status, result["status"])
+		}
+
+		results := result["checks"].(gin.H)
+		for _, name := range []string{"ok", "degraded", "fail"} {
+			check := results[name].(gin.H)
+			if check["status"] != name {
+				t.Errorf("debugmode %v: %s got status %v", debugmode, name, check["status"])
+			}
+			if _, ok := check["error"]; ok != (debugmode && name != "ok") {
+				t.Errorf("debugmode %v: %s got error %v", debugmode, name, check["error"])
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go-micro.dev/v4/metadata"
+	"jochum.dev/jo-micro/logruscomponent"
+)
+
+// credentialHeaders are removed from requests to HTTP upstreams unless the route forwards credentials,
+// httputil.ReverseProxy removes the hop-by-hop headers
+var credentialHeaders = []string{"Authorization", "Cookie"}
+
+var errInvalidUpstreamPath = errors.New("invalid path")
+
+// proxyHTTP forwards the request to a plain HTTP upstream
+func (h *Handler) proxyHTTP(ctx context.Context, c *gin.Context, pr *proxyRoute) {
+	logger := logruscomponent.MustReg(h.cReg).Logger()
+
+	path, err := upstreamPath(c, pr)
+	if err != nil {
+		abortWithError(c, http.StatusBadRequest, "BAD_REQUEST", err)
+		return
+	}
+
+	target, err := h.upstreamURL(pr)
+	if err != nil {
+		logger.WithField("service", pr.service).Error(err)
+		abortWithError(c, http.StatusBadGateway, "BAD_GATEWAY", "upstream not available")
+		return
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL.Scheme = target.Scheme
+			req.URL.Host = target.Host
+			req.URL.Path = joinPaths(joinPaths("/", target.Path), path)
+			req.URL.RawPath = ""
+			req.Host = target.Host
+
+			// The upstream gets the user from auth2 like RPC endpoints do, not the credentials of the client
+			if !pr.route.ForwardCredentials {
+				for _, header := range credentialHeaders {
+					req.Header.Del(header)
+				}
+			}
+
+			// Forward what auth2 gave us for the upstream
+			if md, ok := metadata.FromContext(ctx); ok {
+				for k, v := range md {
+					req.Header.Set(k, v)
+				}
+			}
+		},
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				abortWithBodyError(c, err)
+				return
+			}
+
+			logger.WithField("service", pr.service).WithField("upstream", target.String()).Error(err)
+			abortWithError(c, http.StatusBadGateway, "BAD_GATEWAY", "upstream not available")
+		},
+	}
+
+	proxy.ServeHTTP(c.Writer, c.Request)
+}
+
+// upstreamURL returns the static upstream of the route or the address of a node of its service
+func (h *Handler) upstreamURL(pr *proxyRoute) (*url.URL, error) {
+	if pr.upstream != nil {
+		return pr.upstream, nil
+	}
+
+	next, err := h.cReg.Service().Client().Options().Selector.Select(pr.service)
+	if err != nil {
+		return nil, err
+	}
+	node, err := next()
+	if err != nil {
+		return nil, err
+	}
+
+	return &url.URL{Scheme: "http", Host: node.Address}, nil
+}
+
+// upstreamPath fills the params into the routes UpstreamPath or strips the prefix from the request path,
+// paths with ".." segments are rejected as they could leave the path of the upstream
+func upstreamPath(c *gin.Context, pr *proxyRoute) (string, error) {
+	var path string
+	if pr.route.UpstreamPath == "" {
+		path = strings.TrimPrefix(c.Request.URL.Path, strings.TrimSuffix(pr.basePath, "/"))
+	} else {
+		path = fillParams(c, pr.route.UpstreamPath)
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		if segment == ".." {
+			return "", errInvalidUpstreamPath
+		}
+	}
+
+	return joinPaths("/", path), nil
+}
+
+// fillParams replaces ":name" and "*name" segments of tpl with the route params
+func fillParams(c *gin.Context, tpl string) string {
+	segments := strings.Split(tpl, "/")
+	for idx, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[idx] = strings.TrimPrefix(c.Param(s[1:]), "/")
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
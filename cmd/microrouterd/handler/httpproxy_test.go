@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"jochum.dev/jo-micro/router/internal/proto/routerclientpb"
+)
+
+// closeNotifyRecorder is a ResponseRecorder httputil.ReverseProxy accepts behind gin
+type closeNotifyRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (closeNotifyRecorder) CloseNotify() <-chan bool {
+	return make(chan bool)
+}
+
+func TestProxyHTTP(t *testing.T) {
+	h := newTestHandler(t)
+
+	var got *http.Request
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+	}))
+	defer upstream.Close()
+	upstreamURL, _ := url.Parse(upstream.URL + "/base")
+
+	tests := []struct {
+		name         string
+		upstreamPath string
+		credentials  bool
+		path         string
+		status       int
+		wantPath     string
+		wantAuth     string
+	}{
+		{name: "prefix stripped", path: "/api/files/a/b", status: http.StatusOK, wantPath: "/base/files/a/b"},
+		{name: "template", upstreamPath: "/static/*path", path: "/api/files/a", status: http.StatusOK, wantPath: "/base/static/a"},
+		{name: "dot dot", path: "/api/files/..%2F..%2Fadmin", status: http.StatusBadRequest},
+		{name: "dot dot in template", upstreamPath: "/static/*path", path: "/api/files/..%2Fadmin", status: http.StatusBadRequest},
+		{name: "single dot", path: "/api/files/./a", status: http.StatusOK, wantPath: "/base/files/a"},
+		{name: "credentials", credentials: true, path: "/api/files/a", status: http.StatusOK, wantPath: "/base/files/a", wantAuth: "Bearer secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			pr := &proxyRoute{
+				service:  "files",
+				basePath: "/api",
+				upstream: upstreamURL,
+				route: &routerclientpb.RoutesReply_Route{
+					Type:               "http",
+					UpstreamPath:       tt.upstreamPath,
+					ForwardCredentials: tt.credentials,
+				},
+			}
+
+			r := gin.New()
+			r.GET("/api/files/*path", func(c *gin.Context) {
+				h.proxyHTTP(context.Background(), c, pr)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			req.Header.Set("Cookie", "session=secret")
+			w := closeNotifyRecorder{httptest.NewRecorder()}
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("got status %d, want %d", w.Code, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				if got != nil {
+					t.Errorf("the upstream got %s", got.URL.Path)
+				}
+				return
+			}
+
+			if got.URL.Path != tt.wantPath {
+				t.Errorf("got path %s, want %s", got.URL.Path, tt.wantPath)
+			}
+			if auth := got.Header.Get("Authorization"); auth != tt.wantAuth {
+				t.Errorf("got Authorization %q, want %q", auth, tt.wantAuth)
+			}
+			if cookie := got.Header.Get("Cookie"); (cookie != "") != tt.credentials {
+				t.Errorf("got Cookie %q", cookie)
+			}
+		})
+	}
+}
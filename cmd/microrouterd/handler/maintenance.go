@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go-micro.dev/v4/errors"
+	"jochum.dev/jo-micro/router/cmd/microrouterd/config"
+	"jochum.dev/jo-micro/router/internal/proto/routerserverpb"
+)
+
+// maintenanceMode is the answer for a service, or for all services, in maintenance
+type maintenanceMode struct {
+	body       string
+	retryAfter int
+}
+
+// maintenanceFor returns the maintenance mode of serviceName, the services own mode wins over the global one
+func (h *Handler) maintenanceFor(serviceName string) *maintenanceMode {
+	h.maintenanceMu.RLock()
+	defer h.maintenanceMu.RUnlock()
+
+	if m, ok := h.maintenance[serviceName]; ok {
+		return m
+	}
+
+	// Never lock out the admin API
+	if serviceName == config.Name+"-internal" {
+		return nil
+	}
+
+	return h.maintenance[""]
+}
+
+// abortWithMaintenance answers with 503, Retry-After and the maintenance body
+func (h *Handler) abortWithMaintenance(c *gin.Context, m *maintenanceMode) {
+	if m == nil {
+		m = &maintenanceMode{body: h.maintenanceBody, retryAfter: h.maintenanceRetryAfter}
+	}
+
+	if m.retryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(m.retryAfter))
+	}
+
+	if c.GetBool(grpcContextKey) {
+		abortWithError(c, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "maintenance")
+		return
+	}
+
+	c.Data(http.StatusServiceUnavailable, "application/json; charset=utf-8", []byte(m.body))
+	c.Abort()
+}
+
+// setMaintenance enables or disables the maintenance mode of a service, service == "" is the global mode
+func (h *Handler) setMaintenance(service string, enabled bool, body string, retryAfter int) {
+	h.maintenanceMu.Lock()
+	defer h.maintenanceMu.Unlock()
+
+	if enabled {
+		h.maintenance[service] = &maintenanceMode{body: body, retryAfter: retryAfter}
+	} else {
+		delete(h.maintenance, service)
+	}
+}
+
+// Maintenance toggles the maintenance mode for a service or globally on all instances and returns all active modes
+func (h *Handler) Maintenance(ctx context.Context, in *routerserverpb.MaintenanceRequest, out *routerserverpb.MaintenanceReply) error {
+	body := in.Body
+	if body == "" {
+		body = h.maintenanceBody
+	}
+	if !json.Valid([]byte(body)) {
+		return errors.BadRequest(config.Name, "body is not valid JSON")
+	}
+
+	retryAfter := int(in.RetryAfter)
+	if retryAfter == 0 {
+		retryAfter = h.maintenanceRetryAfter
+	}
+
+	h.setMaintenance(in.Service, in.Enabled, body, retryAfter)
+
+	event := &stateEvent{Maintenance: &maintenanceEvent{Service: in.Service, Enabled: in.Enabled, Body: body, RetryAfter: retryAfter}}
+	if err := h.publishState(ctx, event); err != nil {
+		return errors.InternalServerError(config.Name, "changed the maintenance mode on instance %s only: %s", h.instanceID, err)
+	}
+
+	h.maintenanceMu.RLock()
+	defer h.maintenanceMu.RUnlock()
+
+	services := make([]string, 0, len(h.maintenance))
+	for s := range h.maintenance {
+		services = append(services, s)
+	}
+	sort.Strings(services)
+
+	for _, s := range services {
+		out.Modes = append(out.Modes, &routerserverpb.MaintenanceReply_Mode{
+			Service:    s,
+			Body:       h.maintenance[s].body,
+			RetryAfter: int32(h.maintenance[s].retryAfter),
+		})
+	}
+	out.Instance = h.instanceID
+
+	return nil
+}
+
+// redirect answers with the redirect of a TypeRedirect route
+func redirect(c *gin.Context, pr *proxyRoute) {
+	code := int(pr.route.RedirectCode)
+	if code == 0 {
+		code = http.StatusFound
+	}
+
+	location := fillParams(c, pr.route.Redirect)
+	if c.Request.URL.RawQuery != "" && !strings.Contains(location, "?") {
+		location += "?" + c.Request.URL.RawQuery
+	}
+
+	c.Redirect(code, location)
+	c.Abort()
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"jochum.dev/jo-micro/router"
+	"jochum.dev/jo-micro/router/internal/proto/routerclientpb"
+)
+
+// validTypedParams checks the types and locations of params and that their defaults have the right type
+func validTypedParams(params []*routerclientpb.RoutesReply_Param, arrayStyle string) error {
+	switch arrayStyle {
+	case "", router.ArrayStyleRepeat, router.ArrayStyleComma, router.ArrayStyleBrackets:
+	default:
+		return fmt.Errorf("unknown array style '%s'", arrayStyle)
+	}
+
+	for _, p := range params {
+		if p.Name == "" {
+			return fmt.Errorf("found a typed param without a name")
+		}
+
+		switch p.Location {
+		case "", router.ParamLocationPath, router.ParamLocationQuery, router.ParamLocationHeader:
+		default:
+			return fmt.Errorf("param '%s' has an unknown location '%s'", p.Name, p.Location)
+		}
+
+		switch p.Type {
+		case "", router.ParamTypeString, router.ParamTypeInt, router.ParamTypeFloat, router.ParamTypeBool, router.ParamTypeArray:
+		default:
+			return fmt.Errorf("param '%s' has an unknown type '%s'", p.Name, p.Type)
+		}
+
+		switch p.Items {
+		case "", router.ParamTypeString, router.ParamTypeInt, router.ParamTypeFloat, router.ParamTypeBool:
+		default:
+			return fmt.Errorf("param '%s' has an unknown items type '%s'", p.Name, p.Items)
+		}
+
+		if p.DefaultValue != "" {
+			if _, err := coerceParam(p, defaultValues(p)); err != nil {
+				return fmt.Errorf("default of param '%s': %w", p.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// paramValues returns the values of p from the request, nil if there are none
+func paramValues(c *gin.Context, p *routerclientpb.RoutesReply_Param, arrayStyle string) []string {
+	var values []string
+	switch p.Location {
+	case router.ParamLocationPath:
+		values = splitPathParam(p, c.Param(p.Name))
+	case router.ParamLocationQuery:
+		values = queryValues(c, p, arrayStyle)
+	case router.ParamLocationHeader:
+		values = c.Request.Header.Values(p.Name)
+		if p.Type == router.ParamTypeArray {
+			values = splitValues(values)
+		}
+	default:
+		// Path params win over query params
+		values = splitPathParam(p, c.Param(p.Name))
+		if len(values) == 0 {
+			values = queryValues(c, p, arrayStyle)
+		}
+	}
+
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// queryValues returns all values of an array query param in the routes style, else the values of the param
+func queryValues(c *gin.Context, p *routerclientpb.RoutesReply_Param, arrayStyle string) []string {
+	if p.Type != router.ParamTypeArray {
+		return c.QueryArray(p.Name)
+	}
+
+	switch arrayStyle {
+	case router.ArrayStyleComma:
+		return splitValues(c.QueryArray(p.Name))
+	case router.ArrayStyleBrackets:
+		return append(c.QueryArray(p.Name+"[]"), c.QueryArray(p.Name)...)
+	default:
+		return c.QueryArray(p.Name)
+	}
+}
+
+// splitValues splits comma separated values
+func splitValues(values []string) []string {
+	result := []string{}
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			result = append(result, strings.TrimSpace(part))
+		}
+	}
+	return result
+}
+
+// defaultValues returns the default of p, the default of an array is comma separated
+func defaultValues(p *routerclientpb.RoutesReply_Param) []string {
+	if p.Type == router.ParamTypeArray {
+		return splitValues([]string{p.DefaultValue})
+	}
+	return []string{p.DefaultValue}
+}
+
+func splitPathParam(p *routerclientpb.RoutesReply_Param, value string) []string {
+	if value == "" {
+		return nil
+	}
+	if p.Type == router.ParamTypeArray {
+		return strings.Split(value, ",")
+	}
+	return []string{value}
+}
+
+// coerceParam converts values to the type of p, arrays get all values converted to their items type,
+// all others the first value
+func coerceParam(p *routerclientpb.RoutesReply_Param, values []string) (interface{}, error) {
+	if p.Type == router.ParamTypeArray {
+		result := make([]interface{}, len(values))
+		for idx, v := range values {
+			item, err := coerceValue(p.Items, v)
+			if err != nil {
+				return nil, err
+			}
+			result[idx] = item
+		}
+		return result, nil
+	}
+
+	value := ""
+	if len(values) > 0 {
+		value = values[0]
+	}
+
+	return coerceValue(p.Type, value)
+}
+
+// coerceValue converts a single value to typ
+func coerceValue(typ, value string) (interface{}, error) {
+	switch typ {
+	case "", router.ParamTypeString:
+		return value, nil
+	case router.ParamTypeInt:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not an int", value)
+		}
+		return i, nil
+	case router.ParamTypeFloat:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a float", value)
+		}
+		return f, nil
+	case router.ParamTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a bool", value)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unknown type '%s'", typ)
+	}
+}
+
+// typedParams reads and coerces the typed params of a route, it returns an error per invalid or missing param
+func typedParams(c *gin.Context, route *routerclientpb.RoutesReply_Route) (map[string]interface{}, []fieldError) {
+	result := make(map[string]interface{})
+	errs := []fieldError{}
+
+	for _, p := range route.TypedParams {
+		values := paramValues(c, p, route.ArrayStyle)
+		if values == nil && p.DefaultValue != "" {
+			values = defaultValues(p)
+		}
+
+		if values == nil {
+			if p.Required {
+				errs = append(errs, fieldError{field: p.Name, message: "is required"})
+			}
+			continue
+		}
+
+		v, err := coerceParam(p, values)
+		if err != nil {
+			errs = append(errs, fieldError{field: p.Name, message: err.Error()})
+			continue
+		}
+		result[p.Name] = v
+	}
+
+	return result, errs
+}
+
+// withUntypedParams adds the query and path params of route.Params to params, a typed param of the same name wins
+func withUntypedParams(c *gin.Context, route *routerclientpb.RoutesReply_Route, params map[string]interface{}) map[string]interface{} {
+	typed := make(map[string]bool, len(route.TypedParams))
+	for _, p := range route.TypedParams {
+		typed[p.Name] = true
+	}
+
+	for _, p := range route.Params {
+		if !typed[p] && len(c.Query(p)) > 0 {
+			params[p] = c.Query(p)
+		}
+	}
+	for _, p := range route.Params {
+		if !typed[p] && len(c.Param(p)) > 0 {
+			params[p] = c.Param(p)
+		}
+	}
+
+	return params
+}
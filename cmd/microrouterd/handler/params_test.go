@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"jochum.dev/jo-micro/router"
+	"jochum.dev/jo-micro/router/internal/proto/routerclientpb"
+)
+
+func TestCoerceParam(t *testing.T) {
+	tests := []struct {
+		name    string
+		param   *routerclientpb.RoutesReply_Param
+		values  []string
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "string", param: &routerclientpb.RoutesReply_Param{}, values: []string{"a", "b"}, want: "a"},
+		{name: "int", param: &routerclientpb.RoutesReply_Param{Type: router.ParamTypeInt}, values: []string{"-42"}, want: int64(-42)},
+		{name: "not an int", param: &routerclientpb.RoutesReply_Param{Type: router.ParamTypeInt}, values: []string{"4.2"}, wantErr: true},
+		{name: "float", param: &routerclientpb.RoutesReply_Param{Type: router.ParamTypeFloat}, values: []string{"4.2"}, want: 4.2},
+		{name: "bool", param: &routerclientpb.RoutesReply_Param{Type: router.ParamTypeBool}, values: []string{"true"}, want: true},
+		{name: "not a bool", param: &routerclientpb.RoutesReply_Param{Type: router.ParamTypeBool}, values: []string{"yes"}, wantErr: true},
+		{name: "array", param: &routerclientpb.RoutesReply_Param{Type: router.ParamTypeArray, Items: router.ParamTypeInt}, values: []string{"1", "2"}, want: []interface{}{int64(1), int64(2)}},
+		{name: "array of strings", param: &routerclientpb.RoutesReply_Param{Type: router.ParamTypeArray}, values: []string{"a"}, want: []interface{}{"a"}},
+		{name: "invalid item", param: &routerclientpb.RoutesReply_Param{Type: router.ParamTypeArray, Items: router.ParamTypeInt}, values: []string{"1", "x"}, wantErr: true},
+		{name: "unknown type", param: &routerclientpb.RoutesReply_Param{Type: "date"}, values: []string{"2022-01-01"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := coerceParam(tt.param, tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithUntypedParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	route := &routerclientpb.RoutesReply_Route{
+		Params:      []string{"userId", "limit", "q"},
+		TypedParams: []*routerclientpb.RoutesReply_Param{{Name: "limit", Type: router.ParamTypeInt, DefaultValue: "10"}},
+	}
+
+	var got map[string]interface{}
+	r := gin.New()
+	r.GET("/users/:userId", func(c *gin.Context) {
+		params, errs := typedParams(c, route)
+		if len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		got = withUntypedParams(c, route, params)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42?limit=5&q=abc", nil))
+
+	want := map[string]interface{}{"userId": "42", "limit": int64(5), "q": "abc"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestParamValuesArrayStyles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	array := &routerclientpb.RoutesReply_Param{Name: "tag", Type: router.ParamTypeArray, Location: router.ParamLocationQuery}
+	tests := []struct {
+		name  string
+		style string
+		param *routerclientpb.RoutesReply_Param
+		query string
+		want  []string
+	}{
+		{name: "repeat", style: router.ArrayStyleRepeat, param: array, query: "tag=a&tag=b", want: []string{"a", "b"}},
+		{name: "default is repeat", param: array, query: "tag=a,b&tag=c", want: []string{"a,b", "c"}},
+		{name: "comma", style: router.ArrayStyleComma, param: array, query: "tag=a,%20b&tag=c", want: []string{"a", "b", "c"}},
+		{name: "comma drops empty values", style: router.ArrayStyleComma, param: array, query: "tag=a,,b", want: []string{"a", "b"}},
+		{name: "brackets", style: router.ArrayStyleBrackets, param: array, query: "tag%5B%5D=a&tag%5B%5D=b", want: []string{"a", "b"}},
+		{name: "brackets accept plain names", style: router.ArrayStyleBrackets, param: array, query: "tag%5B%5D=a&tag=b", want: []string{"a", "b"}},
+		{name: "missing", style: router.ArrayStyleComma, param: array, query: "other=a"},
+		{name: "not an array", style: router.ArrayStyleComma, param: &routerclientpb.RoutesReply_Param{Name: "tag", Location: router.ParamLocationQuery}, query: "tag=a,b", want: []string{"a,b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil)
+
+			if got := paramValues(c, tt.param, tt.style); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
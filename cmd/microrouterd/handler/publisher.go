@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go-micro.dev/v4/broker"
+)
+
+// asyncPublisher publishes messages to a broker topic in the background, when the buffer is full messages get dropped
+type asyncPublisher struct {
+	broker  broker.Broker
+	topic   string
+	queue   chan []byte
+	logger  *logrus.Logger
+	dropped uint64
+	done    chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func newAsyncPublisher(b broker.Broker, topic string, size int, logger *logrus.Logger) *asyncPublisher {
+	if size < 1 {
+		size = 1
+	}
+
+	p := &asyncPublisher{
+		broker: b,
+		topic:  topic,
+		queue:  make(chan []byte, size),
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+	go p.run()
+
+	return p
+}
+
+// Publish queues body, it never blocks
+func (p *asyncPublisher) Publish(body []byte) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return
+	}
+
+	select {
+	case p.queue <- body:
+	default:
+		atomic.AddUint64(&p.dropped, 1)
+	}
+}
+
+func (p *asyncPublisher) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		select {
+		case body, ok := <-p.queue:
+			if !ok {
+				return
+			}
+
+			msg := &broker.Message{Header: map[string]string{"Content-Type": "application/json"}, Body: body}
+			err := p.broker.Publish(p.topic, msg)
+			if err != nil && lastErr == nil {
+				// Log once until it works again
+				p.logger.WithField("topic", p.topic).WithField("error", err).Error("failed to publish")
+			} else if err == nil && lastErr != nil {
+				p.logger.WithField("topic", p.topic).Info("publishing again")
+			}
+			lastErr = err
+		case <-ticker.C:
+			if dropped := atomic.SwapUint64(&p.dropped, 0); dropped > 0 {
+				p.logger.WithField("topic", p.topic).WithField("dropped", dropped).Warn("publish buffer full, dropped messages")
+			}
+		}
+	}
+}
+
+// Close publishes what's queued and stops
+func (p *asyncPublisher) Close() error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	<-p.done
+	return nil
+}
@@ -0,0 +1,370 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	libredis "github.com/go-redis/redis/v8"
+	limiter "github.com/ulule/limiter/v3"
+)
+
+const (
+	rateAlgorithmFixed   = "fixed"   // fixed windows from ulule/limiter, the default
+	rateAlgorithmSliding = "sliding" // sliding window counter, weights the previous window
+	rateAlgorithmToken   = "token"   // token bucket, refills Limit tokens per Period up to burst
+)
+
+// rateLimiter counts requests for one rate of a ratelimit
+type rateLimiter struct {
+	formatted string
+	rate      limiter.Rate
+	algorithm string
+	burst     int64
+	store     limiter.Store
+	buckets   bucketStore
+}
+
+// parseRate parses "<limit>-<period>[:<algorithm>[:<burst>]]" for example "10-S", "10-S:sliding" or "10-S:token:20"
+func parseRate(formatted string) (*rateLimiter, error) {
+	parts := strings.Split(formatted, ":")
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("incorrect rate format '%s'", formatted)
+	}
+
+	rate, err := limiter.NewRateFromFormatted(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	l := &rateLimiter{formatted: formatted, rate: rate, algorithm: rateAlgorithmFixed, burst: rate.Limit}
+	if len(parts) > 1 {
+		l.algorithm = parts[1]
+	}
+
+	switch l.algorithm {
+	case rateAlgorithmFixed, rateAlgorithmSliding:
+		if len(parts) > 2 {
+			return nil, fmt.Errorf("incorrect rate format '%s', only token buckets have a burst", formatted)
+		}
+	case rateAlgorithmToken:
+		if len(parts) > 2 {
+			l.burst, err = strconv.ParseInt(parts[2], 10, 64)
+			if err != nil || l.burst < 1 {
+				return nil, fmt.Errorf("incorrect burst in rate '%s'", formatted)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown algorithm in rate '%s'", formatted)
+	}
+
+	return l, nil
+}
+
+// Get counts a request for key
+func (l *rateLimiter) Get(ctx context.Context, key string) (limiter.Context, error) {
+	switch l.algorithm {
+	case rateAlgorithmSliding:
+		return slidingWindow(ctx, l.store, key, l.rate, time.Now())
+	case rateAlgorithmToken:
+		return l.buckets.Take(ctx, key, l.rate, l.burst)
+	default:
+		return l.store.Get(ctx, key, l.rate)
+	}
+}
+
+// Peek returns the state for key without counting a request
+func (l *rateLimiter) Peek(ctx context.Context, key string) (limiter.Context, error) {
+	switch l.algorithm {
+	case rateAlgorithmSliding:
+		return slidingWindowPeek(ctx, l.store, key, l.rate, time.Now())
+	case rateAlgorithmToken:
+		return l.buckets.Peek(ctx, key, l.rate, l.burst)
+	default:
+		return l.store.Peek(ctx, key, l.rate)
+	}
+}
+
+// Reset forgets the requests counted for key
+func (l *rateLimiter) Reset(ctx context.Context, key string) (limiter.Context, error) {
+	switch l.algorithm {
+	case rateAlgorithmSliding:
+		window := time.Now().UnixNano() / int64(l.rate.Period)
+		counterRate := slidingCounterRate(l.rate)
+		if _, err := l.store.Reset(ctx, fmt.Sprintf("%s:%d", key, window-1), counterRate); err != nil {
+			return limiter.Context{}, err
+		}
+		if _, err := l.store.Reset(ctx, fmt.Sprintf("%s:%d", key, window), counterRate); err != nil {
+			return limiter.Context{}, err
+		}
+		return slidingWindowPeek(ctx, l.store, key, l.rate, time.Now())
+	case rateAlgorithmToken:
+		return l.buckets.Reset(ctx, key, l.rate, l.burst)
+	default:
+		return l.store.Reset(ctx, key, l.rate)
+	}
+}
+
+// slidingWindowPeek returns the state of a sliding window without counting a request
+func slidingWindowPeek(ctx context.Context, store limiter.Store, key string, rate limiter.Rate, now time.Time) (limiter.Context, error) {
+	estimated, reset, err := slidingWindowEstimate(ctx, store, key, rate, now)
+	if err != nil {
+		return limiter.Context{}, err
+	}
+
+	remaining := rate.Limit - estimated
+	if remaining < 0 {
+		remaining = 0
+	}
+	return limiter.Context{Limit: rate.Limit, Remaining: remaining, Reset: reset, Reached: estimated >= rate.Limit}, nil
+}
+
+// slidingCounterRate is the rate of the fixed window counters of a sliding window, they are kept for two periods
+// as the current window is the previous one in the next period, the limit is the maximum so counts aren't capped
+func slidingCounterRate(rate limiter.Rate) limiter.Rate {
+	return limiter.Rate{Period: 2 * rate.Period, Limit: math.MaxInt64}
+}
+
+// slidingWindowWeight returns the current window and how much of the previous one is in the last period
+func slidingWindowWeight(rate limiter.Rate, now time.Time) (int64, float64) {
+	window := now.UnixNano() / int64(rate.Period)
+	elapsed := float64(now.UnixNano()-window*int64(rate.Period)) / float64(rate.Period)
+	return window, 1 - elapsed
+}
+
+// slidingWindowEstimate returns the estimated requests in the last period and when the current window ends
+func slidingWindowEstimate(ctx context.Context, store limiter.Store, key string, rate limiter.Rate, now time.Time) (int64, int64, error) {
+	window, weight := slidingWindowWeight(rate, now)
+	counterRate := slidingCounterRate(rate)
+
+	previous, err := store.Peek(ctx, fmt.Sprintf("%s:%d", key, window-1), counterRate)
+	if err != nil {
+		return 0, 0, err
+	}
+	current, err := store.Peek(ctx, fmt.Sprintf("%s:%d", key, window), counterRate)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	estimated := int64(math.Floor(float64(math.MaxInt64-previous.Remaining)*weight)) + math.MaxInt64 - current.Remaining
+
+	return estimated, time.Unix(0, (window+1)*int64(rate.Period)).Unix(), nil
+}
+
+// slidingWindow estimates the requests in the last period from the counts of the current and the previous
+// fixed window, it works with every limiter.Store as it only needs Peek and Increment
+func slidingWindow(ctx context.Context, store limiter.Store, key string, rate limiter.Rate, now time.Time) (limiter.Context, error) {
+	window, weight := slidingWindowWeight(rate, now)
+	counterRate := slidingCounterRate(rate)
+	reset := time.Unix(0, (window+1)*int64(rate.Period)).Unix()
+	currentKey := fmt.Sprintf("%s:%d", key, window)
+
+	// Count the request before checking, the increment is atomic so concurrent requests
+	// on this and other instances can't all pass a check of the same count
+	current, err := store.Increment(ctx, currentKey, 1, counterRate)
+	if err != nil {
+		return limiter.Context{}, err
+	}
+	previous, err := store.Peek(ctx, fmt.Sprintf("%s:%d", key, window-1), counterRate)
+	if err != nil {
+		return limiter.Context{}, err
+	}
+
+	estimated := int64(math.Floor(float64(math.MaxInt64-previous.Remaining)*weight)) + math.MaxInt64 - current.Remaining
+	if estimated > rate.Limit {
+		// Rejected requests don't count, else a client over the limit never gets through again
+		if _, err := store.Increment(ctx, currentKey, -1, counterRate); err != nil {
+			return limiter.Context{}, err
+		}
+		return limiter.Context{Limit: rate.Limit, Remaining: 0, Reset: reset, Reached: true}, nil
+	}
+
+	return limiter.Context{Limit: rate.Limit, Remaining: rate.Limit - estimated, Reset: reset}, nil
+}
+
+// bucketStore keeps token buckets
+type bucketStore interface {
+	Take(ctx context.Context, key string, rate limiter.Rate, burst int64) (limiter.Context, error)
+	Peek(ctx context.Context, key string, rate limiter.Rate, burst int64) (limiter.Context, error)
+	Reset(ctx context.Context, key string, rate limiter.Rate, burst int64) (limiter.Context, error)
+}
+
+// bucketContext builds the limiter.Context for a bucket with tokens left after a take,
+// Reset is when the next token arrives if the take failed else when the bucket is full again
+func bucketContext(now time.Time, rate limiter.Rate, burst int64, tokens float64, taken bool) limiter.Context {
+	perToken := float64(rate.Period) / float64(rate.Limit)
+
+	var reset time.Duration
+	if taken {
+		reset = time.Duration((float64(burst) - tokens) * perToken)
+	} else {
+		reset = time.Duration((1 - tokens) * perToken)
+	}
+
+	return limiter.Context{
+		Limit:     burst,
+		Remaining: int64(math.Floor(tokens)),
+		Reset:     now.Add(reset).Unix(),
+		Reached:   !taken,
+	}
+}
+
+type memoryBucket struct {
+	tokens    float64
+	last      time.Time
+	perSecond float64
+	burst     float64
+}
+
+// memoryBucketStore keeps the buckets in this process
+type memoryBucketStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+func newMemoryBucketStore() *memoryBucketStore {
+	return &memoryBucketStore{buckets: make(map[string]*memoryBucket), lastSweep: time.Now()}
+}
+
+func (s *memoryBucketStore) Take(ctx context.Context, key string, rate limiter.Rate, burst int64) (limiter.Context, error) {
+	now := time.Now()
+	perSecond := float64(rate.Limit) / rate.Period.Seconds()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Full buckets are the same as no bucket, drop them once a minute, each bucket refills with the rate of its limiter
+	if now.Sub(s.lastSweep) > time.Minute {
+		for k, b := range s.buckets {
+			if b.tokens+now.Sub(b.last).Seconds()*b.perSecond >= b.burst {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{tokens: float64(burst), last: now}
+		s.buckets[key] = b
+	}
+	b.perSecond = perSecond
+	b.burst = float64(burst)
+
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*perSecond)
+	b.last = now
+
+	taken := b.tokens >= 1
+	if taken {
+		b.tokens--
+	}
+
+	return bucketContext(now, rate, burst, b.tokens, taken), nil
+}
+
+func (s *memoryBucketStore) Peek(ctx context.Context, key string, rate limiter.Rate, burst int64) (limiter.Context, error) {
+	now := time.Now()
+	perSecond := float64(rate.Limit) / rate.Period.Seconds()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens := float64(burst)
+	if b, ok := s.buckets[key]; ok {
+		tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*perSecond)
+	}
+
+	return bucketContext(now, rate, burst, tokens, tokens >= 1), nil
+}
+
+func (s *memoryBucketStore) Reset(ctx context.Context, key string, rate limiter.Rate, burst int64) (limiter.Context, error) {
+	s.mu.Lock()
+	delete(s.buckets, key)
+	s.mu.Unlock()
+
+	return bucketContext(time.Now(), rate, burst, float64(burst), true), nil
+}
+
+// redisBucketScript refills and takes a token atomically, the bucket expires when it would be full again
+var redisBucketScript = libredis.NewScript(`
+local perMs = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "last")
+local tokens = tonumber(bucket[1]) or burst
+local last = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - last) * perMs)
+local taken = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	taken = 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "last", tostring(now))
+redis.call("PEXPIRE", KEYS[1], math.ceil((burst - tokens) / perMs) + 1)
+return {taken, tostring(tokens)}
+`)
+
+// redisBucketStore keeps the buckets in redis so all router instances share them
+type redisBucketStore struct {
+	client *libredis.Client
+	prefix string
+}
+
+func (s *redisBucketStore) Take(ctx context.Context, key string, rate limiter.Rate, burst int64) (limiter.Context, error) {
+	now := time.Now()
+	perMs := float64(rate.Limit) / float64(rate.Period.Milliseconds())
+
+	result, err := redisBucketScript.Run(ctx, s.client, []string{s.prefix + ":" + key},
+		strconv.FormatFloat(perMs, 'f', -1, 64), burst, now.UnixMilli()).Slice()
+	if err != nil {
+		return limiter.Context{}, err
+	}
+	if len(result) != 2 {
+		return limiter.Context{}, fmt.Errorf("unexpected token bucket result %v", result)
+	}
+
+	taken, _ := result[0].(int64)
+	tokensStr, _ := result[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return limiter.Context{}, err
+	}
+
+	return bucketContext(now, rate, burst, tokens, taken == 1), nil
+}
+
+func (s *redisBucketStore) Peek(ctx context.Context, key string, rate limiter.Rate, burst int64) (limiter.Context, error) {
+	now := time.Now()
+	perMs := float64(rate.Limit) / float64(rate.Period.Milliseconds())
+
+	bucket, err := s.client.HMGet(ctx, s.prefix+":"+key, "tokens", "last").Result()
+	if err != nil {
+		return limiter.Context{}, err
+	}
+
+	tokens := float64(burst)
+	tokensStr, ok1 := bucket[0].(string)
+	lastStr, ok2 := bucket[1].(string)
+	if ok1 && ok2 {
+		t, err1 := strconv.ParseFloat(tokensStr, 64)
+		last, err2 := strconv.ParseInt(lastStr, 10, 64)
+		if err1 == nil && err2 == nil {
+			tokens = math.Min(float64(burst), t+math.Max(0, float64(now.UnixMilli()-last))*perMs)
+		}
+	}
+
+	return bucketContext(now, rate, burst, tokens, tokens >= 1), nil
+}
+
+func (s *redisBucketStore) Reset(ctx context.Context, key string, rate limiter.Rate, burst int64) (limiter.Context, error) {
+	if err := s.client.Del(ctx, s.prefix+":"+key).Err(); err != nil {
+		return limiter.Context{}, err
+	}
+
+	return bucketContext(time.Now(), rate, burst, float64(burst), true), nil
+}
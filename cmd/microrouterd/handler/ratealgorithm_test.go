@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	limiter "github.com/ulule/limiter/v3"
+)
+
+func TestSlidingWindowConcurrent(t *testing.T) {
+	store := newMemoryStore()
+	rate := limiter.Rate{Period: time.Hour, Limit: 10}
+
+	var (
+		wg      sync.WaitGroup
+		allowed atomic.Int64
+	)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lctx, err := slidingWindow(context.Background(), store, "key", rate, time.Now())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !lctx.Reached {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed.Load() != rate.Limit {
+		t.Errorf("allowed %d requests, want %d", allowed.Load(), rate.Limit)
+	}
+
+	// Rejected requests aren't counted
+	lctx, err := slidingWindowPeek(context.Background(), store, "key", rate, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lctx.Remaining != 0 || !lctx.Reached {
+		t.Errorf("got remaining %d reached %v, want 0 true", lctx.Remaining, lctx.Reached)
+	}
+}
+
+func TestMemoryBucketSweepUsesOwnRate(t *testing.T) {
+	s := newMemoryBucketStore()
+	slow := limiter.Rate{Period: time.Hour, Limit: 1}
+	fast := limiter.Rate{Period: time.Second, Limit: 100}
+
+	for i := 0; i < 5; i++ {
+		if _, err := s.Take(context.Background(), "slow", slow, 5); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The fast rate would have refilled the slow bucket by now
+	s.mu.Lock()
+	s.buckets["slow"].last = time.Now().Add(-time.Second)
+	s.lastSweep = time.Now().Add(-2 * time.Minute)
+	s.mu.Unlock()
+
+	if _, err := s.Take(context.Background(), "fast", fast, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	lctx, err := s.Take(context.Background(), "slow", slow, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lctx.Reached {
+		t.Errorf("the slow bucket got refilled by the sweep, %d tokens remaining", lctx.Remaining)
+	}
+}
+
+func TestMemoryStoreKeys(t *testing.T) {
+	store := newMemoryStore()
+	rate := limiter.Rate{Period: time.Hour, Limit: 100}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j <= i; j++ {
+				if _, err := store.Get(context.Background(), fmt.Sprintf("key-%d", i), rate); err != nil {
+					t.Error(err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 10; i++ {
+		lctx, err := store.Peek(context.Background(), fmt.Sprintf("key-%d", i), rate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := rate.Limit - lctx.Remaining; got != int64(i+1) {
+			t.Errorf("key-%d: got count %d, want %d", i, got, i+1)
+		}
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		formatted string
+		limit     int64
+		period    time.Duration
+		algorithm string
+		burst     int64
+		wantErr   bool
+	}{
+		{formatted: "10-S", limit: 10, period: time.Second, algorithm: rateAlgorithmFixed, burst: 10},
+		{formatted: "100-M:sliding", limit: 100, period: time.Minute, algorithm: rateAlgorithmSliding, burst: 100},
+		{formatted: "5-H:token", limit: 5, period: time.Hour, algorithm: rateAlgorithmToken, burst: 5},
+		{formatted: "5-S:token:20", limit: 5, period: time.Second, algorithm: rateAlgorithmToken, burst: 20},
+		{formatted: "5-S:fixed:20", wantErr: true},
+		{formatted: "5-S:token:0", wantErr: true},
+		{formatted: "5-S:token:x", wantErr: true},
+		{formatted: "5-S:leaky", wantErr: true},
+		{formatted: "5-S:token:1:2", wantErr: true},
+		{formatted: "five-S", wantErr: true},
+		{formatted: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.formatted, func(t *testing.T) {
+			l, err := parseRate(tt.formatted)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got no error, rate %+v", l.rate)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if l.rate.Limit != tt.limit || l.rate.Period != tt.period || l.algorithm != tt.algorithm || l.burst != tt.burst {
+				t.Errorf("got %d/%s %s burst %d, want %d/%s %s burst %d", l.rate.Limit, l.rate.Period, l.algorithm, l.burst, tt.limit, tt.period, tt.algorithm, tt.burst)
+			}
+		})
+	}
+}
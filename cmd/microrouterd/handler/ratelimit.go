@@ -0,0 +1,401 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	gopath "path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jochum.dev/jo-micro/auth2"
+	"jochum.dev/jo-micro/logruscomponent"
+	"jochum.dev/jo-micro/router"
+	"jochum.dev/jo-micro/router/cmd/microrouterd/config"
+	"jochum.dev/jo-micro/router/internal/proto/routerclientpb"
+)
+
+// ratelimit are the limiters for one key, they count in scope which is the path of the route or a group
+type ratelimit struct {
+	key      string
+	scope    string
+	limiters []*rateLimiter
+}
+
+// newRatelimits builds the limiters for the legacy ratelimitClientIP/ratelimitUser fields and the ratelimits of a route,
+// routes without limits get the gateway defaults, the gateway ceilings apply to all routes
+func (h *Handler) newRatelimits(serviceName, path string, route *routerclientpb.RoutesReply_Route) ([]*ratelimit, error) {
+	specs := []*routerclientpb.RoutesReply_Ratelimit{}
+	if len(route.RatelimitClientIP) > 0 {
+		specs = append(specs, &routerclientpb.RoutesReply_Ratelimit{Key: router.RatelimitKeyClientIP, Rates: route.RatelimitClientIP})
+	}
+	if route.AuthRequired && len(route.RatelimitUser) > 0 {
+		specs = append(specs, &routerclientpb.RoutesReply_Ratelimit{Key: router.RatelimitKeyUser, Rates: route.RatelimitUser})
+	}
+	for _, spec := range route.Ratelimits {
+		if len(spec.Rates) > 0 {
+			specs = append(specs, spec)
+		}
+	}
+
+	if len(specs) == 0 && h.rlPolicies != nil {
+		specs = h.rlPolicies.defaults.match(serviceName, path)
+	}
+
+	result := make([]*ratelimit, 0, len(specs))
+	for _, spec := range specs {
+		rl, err := h.newRatelimit(spec, path)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, rl)
+	}
+
+	if h.rlPolicies != nil {
+		for _, spec := range h.rlPolicies.ceilings.match(serviceName, path) {
+			// Count ceilings separately, a declared limit with the same rate would count twice
+			rl, err := h.newRatelimit(spec, "ceiling:"+path)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, rl)
+		}
+	}
+
+	return result, nil
+}
+
+func (h *Handler) newRatelimit(spec *routerclientpb.RoutesReply_Ratelimit, scope string) (*ratelimit, error) {
+	if err := validRatelimitKey(spec.Key); err != nil {
+		return nil, err
+	}
+
+	if h.rlStore == nil {
+		return nil, fmt.Errorf("found a route with a %s limiter but there is no limiter store", spec.Key)
+	}
+
+	rl := &ratelimit{key: spec.Key, scope: scope, limiters: make([]*rateLimiter, len(spec.Rates))}
+	if spec.Group != "" {
+		rl.scope = "group:" + spec.Group
+	}
+
+	for idx, formatted := range spec.Rates {
+		l, err := parseRate(formatted)
+		if err != nil {
+			return nil, err
+		}
+		l.store = h.rlStore
+		l.buckets = h.rlBuckets
+
+		rl.limiters[idx] = l
+	}
+
+	return rl, nil
+}
+
+// ratelimitPolicy are limits for the routes of a service and/or the routes matching a path pattern,
+// a pattern ending with "*" matches all paths with that prefix else it's a path.Match pattern
+type ratelimitPolicy struct {
+	service string
+	path    string
+	spec    *routerclientpb.RoutesReply_Ratelimit
+}
+
+type ratelimitPolicyList []*ratelimitPolicy
+
+// ratelimitPolicies are the gateway defaults and ceilings from the flags and the config file
+type ratelimitPolicies struct {
+	defaults ratelimitPolicyList
+	ceilings ratelimitPolicyList
+}
+
+func newRatelimitPolicies(flags map[string][]string, cfg *config.File) (*ratelimitPolicies, error) {
+	result := &ratelimitPolicies{}
+
+	for _, name := range []string{"default_clientip", "default_user", "ceiling_clientip", "ceiling_user"} {
+		rates := flags[name]
+		if len(rates) == 0 {
+			continue
+		}
+
+		kind, key, _ := strings.Cut(name, "_")
+		if key == "clientip" {
+			key = router.RatelimitKeyClientIP
+		}
+		p := &ratelimitPolicy{spec: &routerclientpb.RoutesReply_Ratelimit{Key: key, Rates: rates}}
+		if kind == "default" {
+			result.defaults = append(result.defaults, p)
+		} else {
+			result.ceilings = append(result.ceilings, p)
+		}
+	}
+
+	if cfg != nil {
+		for _, p := range cfg.Ratelimit.Defaults {
+			result.defaults = append(result.defaults, newRatelimitPolicy(p))
+		}
+		for _, p := range cfg.Ratelimit.Ceilings {
+			result.ceilings = append(result.ceilings, newRatelimitPolicy(p))
+		}
+	}
+
+	for _, list := range []ratelimitPolicyList{result.defaults, result.ceilings} {
+		for _, p := range list {
+			if err := validRatelimitKey(p.spec.Key); err != nil {
+				return nil, err
+			}
+			for _, rate := range p.spec.Rates {
+				if _, err := parseRate(rate); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func newRatelimitPolicy(p config.RatelimitPolicy) *ratelimitPolicy {
+	return &ratelimitPolicy{
+		service: p.Service,
+		path:    p.Path,
+		spec:    &routerclientpb.RoutesReply_Ratelimit{Key: p.Key, Rates: p.Rates, Group: p.Group},
+	}
+}
+
+// specificity returns -1 if the policy doesn't match else how specific it is, path beats service beats global
+func (p *ratelimitPolicy) specificity(serviceName, path string) int {
+	if p.service != "" && p.service != serviceName {
+		return -1
+	}
+
+	if p.path != "" {
+		if strings.HasSuffix(p.path, "*") {
+			if !strings.HasPrefix(path, strings.TrimSuffix(p.path, "*")) {
+				return -1
+			}
+		} else if ok, _ := gopath.Match(p.path, path); !ok {
+			return -1
+		}
+		return 2
+	}
+
+	if p.service != "" {
+		return 1
+	}
+	return 0
+}
+
+// match returns the limits of the most specific policies for a route
+func (l ratelimitPolicyList) match(serviceName, path string) []*routerclientpb.RoutesReply_Ratelimit {
+	best := -1
+	result := []*routerclientpb.RoutesReply_Ratelimit{}
+	for _, p := range l {
+		s := p.specificity(serviceName, path)
+		if s < 0 || s < best {
+			continue
+		}
+		if s > best {
+			best = s
+			result = result[:0]
+		}
+		result = append(result, p.spec)
+	}
+
+	return result
+}
+
+func validRatelimitKey(key string) error {
+	switch key {
+	case router.RatelimitKeyClientIP, router.RatelimitKeyUser, router.RatelimitKeyAPIKey:
+		return nil
+	}
+
+	kind, name, ok := strings.Cut(key, ":")
+	if ok && name != "" {
+		switch kind {
+		case "header", "param", "claim":
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown ratelimit key '%s'", key)
+}
+
+// clientChosenKey reports whether the client picks the value of the key, a new value gets a fresh quota
+func clientChosenKey(key string) bool {
+	kind, _, _ := strings.Cut(key, ":")
+	return kind == router.RatelimitKeyAPIKey || kind == "header" || kind == "param"
+}
+
+// lacksClientIPLimit reports whether ratelimits count on values the client picks without a client IP limit next to them
+func lacksClientIPLimit(ratelimits []*ratelimit) bool {
+	clientChosen := false
+	for _, rl := range ratelimits {
+		if rl.key == router.RatelimitKeyClientIP {
+			return false
+		}
+		if clientChosenKey(rl.key) {
+			clientChosen = true
+		}
+	}
+
+	return clientChosen
+}
+
+// needsUser reports whether the key can only be extracted after auth
+func (r *ratelimit) needsUser() bool {
+	return r.key == router.RatelimitKeyUser || strings.HasPrefix(r.key, "claim:")
+}
+
+// value returns what the limiters count on, keys that aren't in the request fall back to the client IP or the user
+func (r *ratelimit) value(c *gin.Context, u *auth2.User) string {
+	kind, name, _ := strings.Cut(r.key, ":")
+
+	var v string
+	switch kind {
+	case router.RatelimitKeyClientIP:
+		return c.ClientIP()
+	case router.RatelimitKeyUser:
+		return u.Id
+	case router.RatelimitKeyAPIKey:
+		v = c.GetHeader("X-API-Key")
+	case "header":
+		v = c.GetHeader(name)
+	case "param":
+		v = c.Param(name)
+	case "claim":
+		if v = u.Metadata[name]; v == "" {
+			return "user:" + u.Id
+		}
+	}
+
+	if v == "" {
+		return "ip:" + c.ClientIP()
+	}
+	return r.key + ":" + v
+}
+
+// rateLimitState are the results of all limiters of a request, they are kept on the gin.Context
+type rateLimitState struct {
+	results []rateLimitResult
+	outcome string // allowed, limited, unavailable or failopen, for the access log
+}
+
+type rateLimitResult struct {
+	limit     int64
+	remaining int64
+	reset     int64 // unix time
+	window    int64 // seconds
+}
+
+const rateLimitStateKey = "router.ratelimits"
+
+// checkRatelimits runs the limiters that need no user if u is nil else those that need a user,
+// it returns false if the request has been aborted
+func (h *Handler) checkRatelimits(c *gin.Context, pr *proxyRoute, u *auth2.User) bool {
+	var state *rateLimitState
+	if v, ok := c.Get(rateLimitStateKey); ok {
+		state = v.(*rateLimitState)
+	} else {
+		state = &rateLimitState{}
+		c.Set(rateLimitStateKey, state)
+	}
+
+	for _, rl := range pr.ratelimits {
+		if rl.needsUser() != (u != nil) {
+			continue
+		}
+
+		value := rl.value(c, u)
+		for _, l := range rl.limiters {
+			context, err := l.Get(c, fmt.Sprintf("%s-%s-%s", rl.scope, l.formatted, value))
+			if err != nil {
+				if h.rlFailOpen {
+					state.outcome = "failopen"
+					continue
+				}
+				state.outcome = "unavailable"
+				if !errors.Is(err, errRatelimitStoreDown) {
+					logruscomponent.MustReg(h.cReg).Logger().WithField("error", err).Error("ratelimit store")
+				}
+				abortWithError(c, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "rate limiter unavailable")
+				return false
+			}
+
+			result := rateLimitResult{
+				limit:     context.Limit,
+				remaining: context.Remaining,
+				reset:     context.Reset,
+				window:    int64(l.rate.Period / time.Second),
+			}
+			state.results = append(state.results, result)
+
+			if h.rlLegacyHeaders {
+				prefix := "X-RateLimit"
+				switch rl.key {
+				case router.RatelimitKeyClientIP:
+					prefix = "X-ClientIPRateLimit"
+				case router.RatelimitKeyUser:
+					prefix = "X-UserRateLimit"
+				}
+				c.Header(prefix+"-Limit", strconv.FormatInt(context.Limit, 10))
+				c.Header(prefix+"-Remaining", strconv.FormatInt(context.Remaining, 10))
+				c.Header(prefix+"-Reset", strconv.FormatInt(context.Reset, 10))
+			}
+
+			if context.Reached {
+				state.outcome = "limited"
+				state.writeHeaders(c, &result)
+				abortWithError(c, http.StatusTooManyRequests, "TOO_MANY_REQUESTS", "too many requests")
+				return false
+			}
+		}
+	}
+
+	if state.outcome == "" && len(state.results) > 0 {
+		state.outcome = "allowed"
+	}
+	state.writeHeaders(c, nil)
+	return true
+}
+
+// writeHeaders sets the RateLimit headers from draft-ietf-httpapi-ratelimit-headers for the window closest to its limit,
+// Retry-After is set if reached is given
+func (s *rateLimitState) writeHeaders(c *gin.Context, reached *rateLimitResult) {
+	if len(s.results) == 0 {
+		return
+	}
+
+	now := time.Now().Unix()
+	resetIn := func(r *rateLimitResult) int64 {
+		if r.reset < now {
+			return 0
+		}
+		return r.reset - now
+	}
+
+	closest := &s.results[0]
+	policies := make([]string, len(s.results))
+	for idx := range s.results {
+		r := &s.results[idx]
+		policies[idx] = fmt.Sprintf("%d;w=%d", r.limit, r.window)
+		if r.remaining < closest.remaining || (r.remaining == closest.remaining && r.reset > closest.reset) {
+			closest = r
+		}
+	}
+	if reached != nil {
+		closest = reached
+	}
+
+	c.Header("RateLimit-Limit", strconv.FormatInt(closest.limit, 10))
+	c.Header("RateLimit-Remaining", strconv.FormatInt(closest.remaining, 10))
+	c.Header("RateLimit-Reset", strconv.FormatInt(resetIn(closest), 10))
+	c.Header("RateLimit-Policy", strings.Join(policies, ", "))
+
+	if reached != nil {
+		c.Header("Retry-After", strconv.FormatInt(resetIn(reached), 10))
+	}
+}
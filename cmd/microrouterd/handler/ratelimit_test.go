@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"jochum.dev/jo-micro/router"
+)
+
+func TestWriteHeaders(t *testing.T) {
+	now := time.Now().Unix()
+	minute := rateLimitResult{limit: 10, remaining: 5, reset: now + 30, window: 60}
+	hour := rateLimitResult{limit: 100, remaining: 5, reset: now + 1800, window: 3600}
+	second := rateLimitResult{limit: 2, remaining: 1, reset: now + 1, window: 1}
+	expired := rateLimitResult{limit: 2, remaining: 0, reset: now - 5, window: 1}
+
+	tests := []struct {
+		name       string
+		results    []rateLimitResult
+		reached    *rateLimitResult
+		limit      string
+		remaining  string
+		reset      int64
+		policy     string
+		retryAfter int64
+	}{
+		{name: "fewest remaining", results: []rateLimitResult{minute, second}, limit: "2", remaining: "1", reset: 1, policy: "10;w=60, 2;w=1", retryAfter: -1},
+		{name: "later reset wins a tie", results: []rateLimitResult{minute, hour}, limit: "100", remaining: "5", reset: 1800, policy: "10;w=60, 100;w=3600", retryAfter: -1},
+		{name: "reached", results: []rateLimitResult{second, hour}, reached: &hour, limit: "100", remaining: "5", reset: 1800, policy: "2;w=1, 100;w=3600", retryAfter: 1800},
+		{name: "reset in the past", results: []rateLimitResult{expired}, reached: &expired, limit: "2", remaining: "0", reset: 0, policy: "2;w=1", retryAfter: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			s := &rateLimitState{results: tt.results}
+			s.writeHeaders(c, tt.reached)
+
+			h := w.Header()
+			if h.Get("RateLimit-Limit") != tt.limit || h.Get("RateLimit-Remaining") != tt.remaining || h.Get("RateLimit-Policy") != tt.policy {
+				t.Errorf("got limit %s remaining %s policy %q", h.Get("RateLimit-Limit"), h.Get("RateLimit-Remaining"), h.Get("RateLimit-Policy"))
+			}
+			// The clock may have moved a second since the results were made
+			if !secondsNear(h.Get("RateLimit-Reset"), tt.reset) {
+				t.Errorf("got reset %s, want %d", h.Get("RateLimit-Reset"), tt.reset)
+			}
+			if tt.retryAfter < 0 {
+				if h.Get("Retry-After") != "" {
+					t.Errorf("got Retry-After %s without reaching a limit", h.Get("Retry-After"))
+				}
+			} else if !secondsNear(h.Get("Retry-After"), tt.retryAfter) {
+				t.Errorf("got Retry-After %s, want %d", h.Get("Retry-After"), tt.retryAfter)
+			}
+		})
+	}
+
+	t.Run("no results", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		(&rateLimitState{}).writeHeaders(c, nil)
+		if len(w.Header()) != 0 {
+			t.Errorf("got headers %v", w.Header())
+		}
+	})
+}
+
+// secondsNear reports whether the header is want or a second less
+func secondsNear(header string, want int64) bool {
+	got, err := strconv.ParseInt(header, 10, 64)
+	return err == nil && (got == want || (want > 0 && got == want-1))
+}
+
+func TestLacksClientIPLimit(t *testing.T) {
+	tests := []struct {
+		keys []string
+		want bool
+	}{
+		{keys: nil},
+		{keys: []string{router.RatelimitKeyUser, router.RatelimitKeyClaim("tenant")}},
+		{keys: []string{router.RatelimitKeyAPIKey}, want: true},
+		{keys: []string{router.RatelimitKeyHeader("X-Tenant")}, want: true},
+		{keys: []string{router.RatelimitKeyParam("id"), router.RatelimitKeyUser}, want: true},
+		{keys: []string{router.RatelimitKeyAPIKey, router.RatelimitKeyClientIP}},
+	}
+
+	for _, tt := range tests {
+		ratelimits := make([]*ratelimit, len(tt.keys))
+		for idx, key := range tt.keys {
+			ratelimits[idx] = &ratelimit{key: key}
+		}
+		if got := lacksClientIPLimit(ratelimits); got != tt.want {
+			t.Errorf("%v: got %v, want %v", tt.keys, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/ulule/limiter/v3"
+	"github.com/ulule/limiter/v3/drivers/store/common"
+)
+
+const (
+	ratelimitOnErrorMemory = "memory" // count in a local memory store while the store is unavailable
+	ratelimitOnErrorOpen   = "open"   // let requests pass while the store is unavailable
+	ratelimitOnErrorClosed = "closed" // answer with 503 while the store is unavailable
+)
+
+// errRatelimitStoreDown is returned while the store is unavailable and there is no fallback
+var errRatelimitStoreDown = errors.New("ratelimit store unavailable")
+
+// failoverStore is a limiter.Store, and with failoverBuckets a bucketStore, that stops using a failing store for retryInterval,
+// in the meantime it uses the fallback stores or returns errRatelimitStoreDown
+type failoverStore struct {
+	store           limiter.Store
+	buckets         bucketStore
+	fallback        limiter.Store
+	fallbackBuckets bucketStore
+	retryInterval   time.Duration
+	logger          *logrus.Logger
+
+	mu        sync.Mutex
+	lastErr   error
+	downSince time.Time
+	retryAt   time.Time
+}
+
+func newFailoverStore(store limiter.Store, buckets bucketStore, onError string, logger *logrus.Logger) (*failoverStore, error) {
+	s := &failoverStore{store: store, buckets: buckets, retryInterval: 5 * time.Second, logger: logger}
+
+	switch onError {
+	case ratelimitOnErrorMemory:
+		s.fallback = newMemoryStore()
+		s.fallbackBuckets = newMemoryBucketStore()
+	case ratelimitOnErrorOpen, ratelimitOnErrorClosed:
+	default:
+		return nil, fmt.Errorf("unknown ratelimit on error policy '%s'", onError)
+	}
+
+	return s, nil
+}
+
+// available returns false while the store is marked down and it's not time to retry yet
+func (s *failoverStore) available() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastErr == nil || !time.Now().Before(s.retryAt)
+}
+
+// result records the outcome of a call to the store and logs state changes
+func (s *failoverStore) result(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		if s.lastErr != nil {
+			s.logger.WithField("downtime", time.Since(s.downSince).String()).Info("ratelimit store is available again")
+			s.lastErr = nil
+		}
+		return
+	}
+
+	if s.lastErr == nil {
+		s.logger.WithField("error", err).Error("ratelimit store is unavailable")
+		s.downSince = time.Now()
+	}
+	s.lastErr = err
+	s.retryAt = time.Now().Add(s.retryInterval)
+}
+
+// do runs fn with the stores or with the fallbacks while the stores are unavailable
+func (s *failoverStore) do(fn func(limiter.Store, bucketStore) (limiter.Context, error)) (limiter.Context, error) {
+	if s.available() {
+		lctx, err := fn(s.store, s.buckets)
+		s.result(err)
+		if err == nil {
+			return lctx, nil
+		}
+	}
+
+	if s.fallback == nil {
+		return limiter.Context{}, errRatelimitStoreDown
+	}
+
+	return fn(s.fallback, s.fallbackBuckets)
+}
+
+// Health returns the last error of the store while it's unavailable, it probes the store when it's time to retry
+func (s *failoverStore) Health(ctx context.Context) error {
+	if s.available() {
+		s.mu.Lock()
+		down := s.lastErr != nil
+		s.mu.Unlock()
+
+		if down {
+			_, err := s.store.Peek(ctx, "health", limiter.Rate{Period: time.Second, Limit: 1})
+			s.result(err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lastErr != nil {
+		return fmt.Errorf("ratelimit store unavailable since %s: %w", s.downSince.Format(time.RFC3339), s.lastErr)
+	}
+	return nil
+}
+
+func (s *failoverStore) Get(ctx context.Context, key string, rate limiter.Rate) (limiter.Context, error) {
+	return s.do(func(store limiter.Store, _ bucketStore) (limiter.Context, error) {
+		return store.Get(ctx, key, rate)
+	})
+}
+
+func (s *failoverStore) Peek(ctx context.Context, key string, rate limiter.Rate) (limiter.Context, error) {
+	return s.do(func(store limiter.Store, _ bucketStore) (limiter.Context, error) {
+		return store.Peek(ctx, key, rate)
+	})
+}
+
+func (s *failoverStore) Reset(ctx context.Context, key string, rate limiter.Rate) (limiter.Context, error) {
+	return s.do(func(store limiter.Store, _ bucketStore) (limiter.Context, error) {
+		return store.Reset(ctx, key, rate)
+	})
+}
+
+func (s *failoverStore) Increment(ctx context.Context, key string, count int64, rate limiter.Rate) (limiter.Context, error) {
+	return s.do(func(store limiter.Store, _ bucketStore) (limiter.Context, error) {
+		return store.Increment(ctx, key, count, rate)
+	})
+}
+
+// failoverBuckets is the bucketStore side of a failoverStore, the method names of both interfaces clash
+type failoverBuckets struct {
+	*failoverStore
+}
+
+func (s failoverBuckets) Take(ctx context.Context, key string, rate limiter.Rate, burst int64) (limiter.Context, error) {
+	return s.do(func(_ limiter.Store, buckets bucketStore) (limiter.Context, error) {
+		return buckets.Take(ctx, key, rate, burst)
+	})
+}
+
+func (s failoverBuckets) Peek(ctx context.Context, key string, rate limiter.Rate, burst int64) (limiter.Context, error) {
+	return s.do(func(_ limiter.Store, buckets bucketStore) (limiter.Context, error) {
+		return buckets.Peek(ctx, key, rate, burst)
+	})
+}
+
+func (s failoverBuckets) Reset(ctx context.Context, key string, rate limiter.Rate, burst int64) (limiter.Context, error) {
+	return s.do(func(_ limiter.Store, buckets bucketStore) (limiter.Context, error) {
+		return buckets.Reset(ctx, key, rate, burst)
+	})
+}
+
+// memoryStore is a limiter.Store in this process, ulule's memory store keys its counters with strings of pooled
+// buffers which get overwritten once there's more than one key
+type memoryStore struct {
+	mu        sync.Mutex
+	counters  map[string]*memoryCounter
+	lastSweep time.Time
+}
+
+type memoryCounter struct {
+	count   int64
+	expires time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{counters: make(map[string]*memoryCounter), lastSweep: time.Now()}
+}
+
+// counter returns the unexpired counter of key, nil if there is none, s.mu must be held
+func (s *memoryStore) counter(key string, now time.Time) *memoryCounter {
+	// Drop expired counters once a minute
+	if now.Sub(s.lastSweep) > time.Minute {
+		for k, c := range s.counters {
+			if !now.Before(c.expires) {
+				delete(s.counters, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	c, ok := s.counters[key]
+	if !ok || !now.Before(c.expires) {
+		return nil
+	}
+	return c
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string, rate limiter.Rate) (limiter.Context, error) {
+	return s.Increment(ctx, key, 1, rate)
+}
+
+func (s *memoryStore) Peek(ctx context.Context, key string, rate limiter.Rate) (limiter.Context, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c := s.counter(key, now); c != nil {
+		return common.GetContextFromState(now, rate, c.expires, c.count), nil
+	}
+	return common.GetContextFromState(now, rate, now.Add(rate.Period), 0), nil
+}
+
+func (s *memoryStore) Reset(ctx context.Context, key string, rate limiter.Rate) (limiter.Context, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.counters, key)
+	return common.GetContextFromState(now, rate, now.Add(rate.Period), 0), nil
+}
+
+func (s *memoryStore) Increment(ctx context.Context, key string, count int64, rate limiter.Rate) (limiter.Context, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.counter(key, now)
+	if c == nil {
+		c = &memoryCounter{expires: now.Add(rate.Period)}
+		s.counters[key] = c
+	}
+	c.count += count
+
+	return common.GetContextFromState(now, rate, c.expires, c.count), nil
+}
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"jochum.dev/jo-micro/logruscomponent"
+	"jochum.dev/jo-micro/router/cmd/microrouterd/config"
+	"jochum.dev/jo-micro/router/internal/proto/routerclientpb"
+)
+
+// rewriteRule rewrites the path of requests matching match, match is a regexp when it starts with "^" else a gin like template
+type rewriteRule struct {
+	method  string
+	match   *regexp.Regexp
+	replace string
+}
+
+func newRewriteRule(method, match, replace string) (*rewriteRule, error) {
+	if match == "" {
+		return nil, fmt.Errorf("a rewrite rule needs a match")
+	}
+
+	if !strings.HasPrefix(match, "^") {
+		match = templateRegexp(match)
+		replace = templateReplace(replace)
+	}
+
+	re, err := regexp.Compile(match)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rewriteRule{method: strings.ToUpper(method), match: re, replace: replace}, nil
+}
+
+// templateRegexp converts a template like "/users/:userId/*rest" into an anchored regexp with named groups
+func templateRegexp(tpl string) string {
+	segments := strings.Split(tpl, "/")
+	for idx, s := range segments {
+		switch {
+		case strings.HasPrefix(s, ":"):
+			segments[idx] = fmt.Sprintf("(?P<%s>[^/]+)", s[1:])
+		case strings.HasPrefix(s, "*"):
+			segments[idx] = fmt.Sprintf("(?P<%s>.*)", s[1:])
+		default:
+			segments[idx] = regexp.QuoteMeta(s)
+		}
+	}
+
+	return "^" + strings.Join(segments, "/") + "$"
+}
+
+// templateReplace converts a template like "/users/:userId/*rest" into a regexp replacement
+func templateReplace(tpl string) string {
+	segments := strings.Split(tpl, "/")
+	for idx, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[idx] = fmt.Sprintf("${%s}", s[1:])
+		} else {
+			segments[idx] = strings.ReplaceAll(s, "$", "$$")
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// routeRewrites compiles the rules of a route, they rewrite matching requests to the route itself,
+// rules of services may only rewrite to the route or below the routerURI of the service
+func routeRewrites(method, path, basePath string, static bool, rewrites []*routerclientpb.RoutesReply_Rewrite) ([]*rewriteRule, error) {
+	result := make([]*rewriteRule, 0, len(rewrites))
+	for _, rw := range rewrites {
+		if !static && rw.Replace != "" && rw.Replace != path && !withinBasePath(rewriteTargetPrefix(rw.Replace), basePath) {
+			return nil, fmt.Errorf("the rewrite of '%s' to '%s' leaves the paths of the service", rw.Match, rw.Replace)
+		}
+
+		replace := rw.Replace
+		if replace == "" {
+			replace = path
+			if strings.HasPrefix(rw.Match, "^") {
+				replace = templateReplace(path)
+			}
+		}
+
+		rule, err := newRewriteRule(method, rw.Match, replace)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, rule)
+	}
+
+	return result, nil
+}
+
+// rewriteTargetPrefix returns the fixed part of a replacement, up to the first param or group
+func rewriteTargetPrefix(replace string) string {
+	if idx := strings.IndexAny(replace, "$:*"); idx >= 0 {
+		return replace[:idx]
+	}
+	return replace
+}
+
+// withinBasePath reports whether a path starting with prefix stays below basePath, a basePath of "/" has no paths of its own
+func withinBasePath(prefix, basePath string) bool {
+	base := strings.TrimSuffix(basePath, "/")
+	if base == "" {
+		return false
+	}
+
+	for _, segment := range strings.Split(prefix, "/") {
+		if segment == ".." {
+			return false
+		}
+	}
+
+	return prefix == base || strings.HasPrefix(prefix, base+"/")
+}
+
+// rewriteSpecificity orders rules, exact matches before prefixes and longer prefixes before shorter ones
+func rewriteSpecificity(rule *rewriteRule) (bool, int) {
+	prefix, complete := rule.match.LiteralPrefix()
+	return complete, len(prefix)
+}
+
+// parseGlobalRewrites compiles the rules from the "router_rewrite" flag and the config file
+func parseGlobalRewrites(flagRules []string, cfg *config.File) ([]*rewriteRule, error) {
+	result := []*rewriteRule{}
+	for _, r := range flagRules {
+		match, replace, ok := strings.Cut(r, "=>")
+		if !ok {
+			return nil, fmt.Errorf("invalid rewrite rule '%s', use <match>=><replace>", r)
+		}
+
+		rule, err := newRewriteRule("", strings.TrimSpace(match), strings.TrimSpace(replace))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, rule)
+	}
+
+	if cfg != nil {
+		for _, r := range cfg.Rewrites {
+			rule, err := newRewriteRule(r.Method, r.Match, r.Replace)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, rule)
+		}
+	}
+
+	return result, nil
+}
+
+// updateRewrites publishes the global rules in their order followed by the rules of all routes,
+// the most specific first, for RewriteHandler
+func (h *Handler) updateRewrites() {
+	pathMethods := make([]string, 0, len(h.routes))
+	for pathMethod := range h.routes {
+		pathMethods = append(pathMethods, pathMethod)
+	}
+	sort.Strings(pathMethods)
+
+	routeRules := []*rewriteRule{}
+	current := make(map[*rewriteRule]bool)
+	for _, pathMethod := range pathMethods {
+		pr := h.routes[pathMethod]
+		for _, rule := range pr.rewrites {
+			current[rule] = true
+			if !pr.static && h.capturesOtherService(pr.service, rule) {
+				continue
+			}
+			routeRules = append(routeRules, rule)
+		}
+	}
+	for rule := range h.rejectedRewrites {
+		if !current[rule] {
+			delete(h.rejectedRewrites, rule)
+		}
+	}
+	sort.SliceStable(routeRules, func(i, j int) bool {
+		iComplete, iLen := rewriteSpecificity(routeRules[i])
+		jComplete, jLen := rewriteSpecificity(routeRules[j])
+		if iComplete != jComplete {
+			return iComplete
+		}
+		return iLen > jLen
+	})
+
+	h.rewrites.Store(append(append([]*rewriteRule{}, h.globalRewrites...), routeRules...))
+}
+
+// capturesOtherService reports whether rule matches a route of another service, such rules are dropped and logged once
+func (h *Handler) capturesOtherService(service string, rule *rewriteRule) bool {
+	for pathMethod, pr := range h.routes {
+		method, path, _ := strings.Cut(pathMethod, ":")
+		if pr.service == service || (rule.method != "" && rule.method != method) {
+			continue
+		}
+		if !rule.match.MatchString(samplePath(path)) {
+			continue
+		}
+
+		if !h.rejectedRewrites[rule] {
+			h.rejectedRewrites[rule] = true
+			logruscomponent.MustReg(h.cReg).Logger().
+				WithField("service", service).
+				WithField("match", rule.match.String()).
+				WithField("route", pathMethod).
+				WithField("owner", pr.service).
+				Error("ignoring a rewrite rule that matches a route of another service")
+		}
+		return true
+	}
+
+	return false
+}
+
+// samplePath fills the params of a gin path with a value, for matching rules against it
+func samplePath(path string) string {
+	segments := strings.Split(path, "/")
+	for idx, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[idx] = "x"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// RewriteHandler rewrites the request path with the first matching rule before next routes it
+func (h *Handler) RewriteHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rules, _ := h.rewrites.Load().([]*rewriteRule)
+		for _, rule := range rules {
+			if rule.method != "" && rule.method != r.Method {
+				continue
+			}
+			if !rule.match.MatchString(r.URL.Path) {
+				continue
+			}
+
+			r.URL.Path = rule.match.ReplaceAllString(r.URL.Path, rule.replace)
+			r.URL.RawPath = ""
+			break
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
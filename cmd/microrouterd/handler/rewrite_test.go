@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"jochum.dev/jo-micro/router/internal/proto/routerclientpb"
+)
+
+func TestTemplateRegexp(t *testing.T) {
+	tests := []struct {
+		tpl   string
+		want  string
+		match map[string]bool
+	}{
+		{tpl: "/users", want: "^/users$", match: map[string]bool{"/users": true, "/users/1": false}},
+		{tpl: "/users/:userId", want: "^/users/(?P<userId>[^/]+)$", match: map[string]bool{"/users/1": true, "/users/": false, "/users/1/posts": false}},
+		{tpl: "/files/*path", want: "^/files/(?P<path>.*)$", match: map[string]bool{"/files/a/b": true, "/files/": true}},
+		{tpl: "/v1.0/:id", want: `^/v1\.0/(?P<id>[^/]+)$`, match: map[string]bool{"/v1.0/1": true, "/v1x0/1": false}},
+	}
+
+	for _, tt := range tests {
+		got := templateRegexp(tt.tpl)
+		if got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.tpl, got, tt.want)
+			continue
+		}
+
+		re := regexp.MustCompile(got)
+		for path, want := range tt.match {
+			if re.MatchString(path) != want {
+				t.Errorf("%s: matching %s isn't %v", tt.tpl, path, want)
+			}
+		}
+	}
+}
+
+func TestRouteRewritesScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		basePath string
+		static   bool
+		match    string
+		replace  string
+		wantErr  bool
+	}{
+		{name: "to the route", basePath: "/api/users", match: "/api/v2/users/:userId"},
+		{name: "to the route explicitly", basePath: "/api/users", match: "/api/v2/users/:userId", replace: "/api/users/:userId"},
+		{name: "below the routerURI", basePath: "/api/users", match: "^/old/(.*)$", replace: "/api/users/$1"},
+		{name: "other service", basePath: "/api/users", match: "/users/:userId", replace: "/api/admin/:userId", wantErr: true},
+		{name: "same prefix", basePath: "/api/users", match: "/users/:userId", replace: "/api/usersx/:userId", wantErr: true},
+		{name: "dot dot", basePath: "/api/users", match: "/users/:userId", replace: "/api/users/../admin/:userId", wantErr: true},
+		{name: "only a group", basePath: "/api/users", match: "^/(.*)$", replace: "$1", wantErr: true},
+		{name: "global route", basePath: "/", match: "/users/:userId", replace: "/admin/:userId", wantErr: true},
+		{name: "static route", basePath: "/", static: true, match: "/users/:userId", replace: "/admin/:userId"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := routeRewrites("GET", "/api/users/:userId", tt.basePath, tt.static, []*routerclientpb.RoutesReply_Rewrite{{Match: tt.match, Replace: tt.replace}})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateRewrites(t *testing.T) {
+	h := newTestHandler(t)
+
+	addRoute := func(service, routerURI, path string, rewrites ...*routerclientpb.RoutesReply_Rewrite) {
+		h.registerRoute(service, "1", routerURI, &routerclientpb.RoutesReply_Route{Method: "GET", Path: path, Endpoint: "E", Rewrites: rewrites}, false)
+	}
+
+	// Registered in pathMethod order the prefix rule would win
+	addRoute("a", "/a", "/all", &routerclientpb.RoutesReply_Rewrite{Match: "^/legacy/.*$", Replace: "/a/all"})
+	addRoute("b", "/b", "/users", &routerclientpb.RoutesReply_Rewrite{Match: "/legacy/users"})
+	// Captures the route of service a
+	addRoute("c", "/c", "/steal", &routerclientpb.RoutesReply_Rewrite{Match: "/a/all"})
+	h.publish()
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/legacy/users", "/b/users"},
+		{"/legacy/other", "/a/all"},
+		{"/a/all", "/a/all"},
+	}
+	for _, tt := range tests {
+		var got string
+		handler := h.RewriteHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.URL.Path
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
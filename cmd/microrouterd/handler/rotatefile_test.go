@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxBackups int
+		writes     []string
+		want       map[string]string
+	}{
+		{
+			name:   "no rotation below maxSize",
+			writes: []string{"aaaa", "bbbb"},
+			want:   map[string]string{"access.log": "aaaabbbb"},
+		},
+		{
+			name:       "rotates",
+			maxBackups: 2,
+			writes:     []string{"aaaaaaaa", "bbbbbbbb", "cccccccc"},
+			want:       map[string]string{"access.log": "cccccccc", "access.log.1": "bbbbbbbb", "access.log.2": "aaaaaaaa"},
+		},
+		{
+			name:       "oldest backup falls off",
+			maxBackups: 1,
+			writes:     []string{"aaaaaaaa", "bbbbbbbb", "cccccccc"},
+			want:       map[string]string{"access.log": "cccccccc", "access.log.1": "bbbbbbbb"},
+		},
+		{
+			name:   "no backups",
+			writes: []string{"aaaaaaaa", "bbbbbbbb"},
+			want:   map[string]string{"access.log": "bbbbbbbb"},
+		},
+		{
+			name:   "a write bigger than maxSize",
+			writes: []string{"aaaaaaaaaaaaaaaaaaaa"},
+			want:   map[string]string{"access.log": "aaaaaaaaaaaaaaaaaaaa"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			f, err := newRotatingFile(filepath.Join(dir, "access.log"), 10, tt.maxBackups)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.writes {
+				if _, err := f.Write([]byte(w)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := f.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != len(tt.want) {
+				t.Errorf("got %d files, want %d", len(entries), len(tt.want))
+			}
+			for name, want := range tt.want {
+				data, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Error(err)
+					continue
+				}
+				if string(data) != want {
+					t.Errorf("%s: got %q, want %q", name, data, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRotatingFileAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	if err := os.WriteFile(path, []byte("aaaaaaaa"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The size of the existing file counts
+	f, err := newRotatingFile(path, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("bbbb")); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if data, _ := os.ReadFile(path + ".1"); string(data) != "aaaaaaaa" {
+		t.Errorf("got backup %q, want aaaaaaaa", data)
+	}
+}
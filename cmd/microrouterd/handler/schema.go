@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// compileSchema compiles the JSON Schema of a route, services can't reference other documents
+func compileSchema(schema string) (*jsonschema.Schema, error) {
+	if schema == "" {
+		return nil, nil
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.LoadURL = func(s string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("schema references '%s', external schemas are not supported", s)
+	}
+
+	url := "route:///schema.json"
+	if err := compiler.AddResource(url, strings.NewReader(schema)); err != nil {
+		return nil, err
+	}
+
+	return compiler.Compile(url)
+}
+
+// validateRequest validates the bound request against the routes schema, it aborts with the field errors if it's invalid
+func validateRequest(c *gin.Context, pr *proxyRoute, request gin.H) bool {
+	if pr.schema == nil {
+		return true
+	}
+
+	// The validator needs the types encoding/json decodes to
+	data, err := json.Marshal(request)
+	if err != nil {
+		abortWithBodyError(c, err)
+		return false
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		abortWithBodyError(c, err)
+		return false
+	}
+
+	err = pr.schema.Validate(doc)
+	if err == nil {
+		return true
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		abortWithBodyError(c, err)
+		return false
+	}
+
+	abortWithFieldErrors(c, schemaFieldErrors(validationErr))
+	return false
+}
+
+// schemaFieldErrors flattens the validation error to the errors of the fields that caused it
+func schemaFieldErrors(ve *jsonschema.ValidationError) []fieldError {
+	if len(ve.Causes) == 0 {
+		field := strings.ReplaceAll(strings.TrimPrefix(ve.InstanceLocation, "/"), "/", ".")
+		return []fieldError{{field: field, message: ve.Message}}
+	}
+
+	result := []fieldError{}
+	for _, cause := range ve.Causes {
+		result = append(result, schemaFieldErrors(cause)...)
+	}
+	return result
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		err    string
+	}{
+		{name: "no schema"},
+		{name: "local ref", schema: `{"$defs":{"id":{"type":"integer"}},"properties":{"id":{"$ref":"#/$defs/id"}}}`},
+		{name: "external ref", schema: `{"properties":{"id":{"$ref":"https://example.com/id.json"}}}`, err: "external schemas are not supported"},
+		{name: "file ref", schema: `{"$ref":"file:///etc/passwd"}`, err: "external schemas are not supported"},
+		{name: "invalid JSON", schema: `{"type":`, err: "schema.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := compileSchema(tt.schema)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if (schema == nil) != (tt.schema == "") {
+					t.Errorf("got schema %v", schema)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got %v, want an error with %q", err, tt.err)
+			}
+		})
+	}
+}
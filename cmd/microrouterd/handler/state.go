@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"go-micro.dev/v4/client"
+	"go-micro.dev/v4/server"
+	"jochum.dev/jo-micro/logruscomponent"
+)
+
+// stateEvent is published to the state topic when an admin changes the state of an instance, the other instances apply it
+type stateEvent struct {
+	// Instance is the server ID of the instance that published the event
+	Instance    string            `json:"instance"`
+	Maintenance *maintenanceEvent `json:"maintenance,omitempty"`
+	Route       *routeEvent       `json:"route,omitempty"`
+	Ratelimit   *ratelimitEvent   `json:"ratelimit,omitempty"`
+}
+
+// maintenanceEvent toggles the maintenance mode of a service, body and retryAfter are resolved by the sender
+type maintenanceEvent struct {
+	Service    string `json:"service"`
+	Enabled    bool   `json:"enabled"`
+	Body       string `json:"body,omitempty"`
+	RetryAfter int    `json:"retry_after,omitempty"`
+}
+
+// routeEvent enables or disables a route
+type routeEvent struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Enabled bool   `json:"enabled"`
+}
+
+// ratelimitEvent resets the ratelimits of a route for a key
+type ratelimitEvent struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Key    string `json:"key"`
+}
+
+// subscribeState subscribes to the state topic without a queue so every instance gets every event
+func (h *Handler) subscribeState(srv server.Server) error {
+	if h.stateTopic == "" {
+		return nil
+	}
+
+	return srv.Subscribe(srv.NewSubscriber(h.stateTopic, h.applyStateEvent))
+}
+
+// publishState sends a state change of this instance to the others, it's a noop without a state topic
+func (h *Handler) publishState(ctx context.Context, event *stateEvent) error {
+	if h.stateTopic == "" {
+		return nil
+	}
+
+	event.Instance = h.instanceID
+	c := h.cReg.Service().Client()
+	return c.Publish(ctx, c.NewMessage(h.stateTopic, event, client.WithMessageContentType("application/json")))
+}
+
+// applyStateEvent applies the state change of another instance
+func (h *Handler) applyStateEvent(ctx context.Context, event *stateEvent) error {
+	if event.Instance == h.instanceID {
+		return nil
+	}
+
+	logger := logruscomponent.MustReg(h.cReg).Logger().WithField("instance", event.Instance)
+	if m := event.Maintenance; m != nil {
+		h.setMaintenance(m.Service, m.Enabled, m.Body, m.RetryAfter)
+		logger.WithField("service", m.Service).WithField("enabled", m.Enabled).Info("maintenance mode changed by another instance")
+	}
+
+	if r := event.Route; r != nil {
+		h.setRouteEnabled(fmt.Sprintf("%s:%s", r.Method, r.Path), r.Enabled)
+		logger.WithField("route", fmt.Sprintf("%s %s", r.Method, r.Path)).WithField("enabled", r.Enabled).Info("route changed by another instance")
+	}
+
+	if r := event.Ratelimit; r != nil {
+		// Routes this instance doesn't know have nothing to reset
+		if pr, ok := h.loadTable().routes[fmt.Sprintf("%s:%s", r.Method, r.Path)]; ok {
+			if err := resetRatelimits(ctx, pr, r.Key); err != nil {
+				logger.WithField("route", fmt.Sprintf("%s %s", r.Method, r.Path)).WithField("error", err).Error("failed to reset the ratelimit of another instance")
+			}
+		}
+	}
+
+	return nil
+}
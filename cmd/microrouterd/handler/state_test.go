@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"testing"
+)
+
+func TestApplyStateEvent(t *testing.T) {
+	h := newTestHandler(t)
+	h.instanceID = "a"
+
+	own := &stateEvent{Instance: "a", Maintenance: &maintenanceEvent{Service: "users", Enabled: true, Body: "{}"}}
+	if err := h.applyStateEvent(context.Background(), own); err != nil {
+		t.Fatal(err)
+	}
+	if h.maintenanceFor("users") != nil {
+		t.Error("applied an event of its own")
+	}
+
+	other := &stateEvent{Instance: "b", Maintenance: &maintenanceEvent{Service: "users", Enabled: true, Body: "{}", RetryAfter: 60}}
+	if err := h.applyStateEvent(context.Background(), other); err != nil {
+		t.Fatal(err)
+	}
+	if m := h.maintenanceFor("users"); m == nil || m.retryAfter != 60 {
+		t.Errorf("got maintenance mode %+v, want retryAfter 60", m)
+	}
+
+	other.Maintenance.Enabled = false
+	if err := h.applyStateEvent(context.Background(), other); err != nil {
+		t.Fatal(err)
+	}
+	if h.maintenanceFor("users") != nil {
+		t.Error("the maintenance mode is still enabled")
+	}
+}
+
+func TestApplyStateEventRoute(t *testing.T) {
+	h := newTestHandler(t)
+	h.instanceID = "a"
+
+	event := &stateEvent{Instance: "b", Route: &routeEvent{Method: "GET", Path: "/api/users", Enabled: false}}
+	if err := h.applyStateEvent(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	if !h.routeDisabled("GET:/api/users") {
+		t.Error("the route isn't disabled")
+	}
+}
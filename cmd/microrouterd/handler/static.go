@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"jochum.dev/jo-micro/logruscomponent"
+	"jochum.dev/jo-micro/router/cmd/microrouterd/config"
+	"jochum.dev/jo-micro/router/internal/proto/routerclientpb"
+)
+
+// staticRoute converts a route from the config file into the format services send us
+func staticRoute(r config.Route) *routerclientpb.RoutesReply_Route {
+	rewrites := make([]*routerclientpb.RoutesReply_Rewrite, len(r.Rewrites))
+	for idx, rw := range r.Rewrites {
+		rewrites[idx] = &routerclientpb.RoutesReply_Rewrite{Match: rw.Match, Replace: rw.Replace}
+	}
+
+	ratelimits := make([]*routerclientpb.RoutesReply_Ratelimit, len(r.Ratelimits))
+	for idx, rl := range r.Ratelimits {
+		ratelimits[idx] = &routerclientpb.RoutesReply_Ratelimit{Key: rl.Key, Rates: rl.Rates, Group: rl.Group}
+	}
+
+	typedParams := make([]*routerclientpb.RoutesReply_Param, len(r.TypedParams))
+	for idx, p := range r.TypedParams {
+		typedParams[idx] = &routerclientpb.RoutesReply_Param{
+			Name:         p.Name,
+			Type:         p.Type,
+			Location:     p.Location,
+			Required:     p.Required,
+			DefaultValue: p.Default,
+			Items:        p.Items,
+		}
+	}
+
+	return &routerclientpb.RoutesReply_Route{
+		IsGlobal:             r.IsGlobal,
+		Type:                 r.Type,
+		Method:               r.Method,
+		Path:                 r.Path,
+		Endpoint:             r.Endpoint,
+		Params:               r.Params,
+		TypedParams:          typedParams,
+		ArrayStyle:           r.ArrayStyle,
+		AuthRequired:         r.AuthRequired,
+		RatelimitClientIP:    r.RatelimitClientIP,
+		RatelimitUser:        r.RatelimitUser,
+		Ratelimits:           ratelimits,
+		Upstream:             r.Upstream,
+		UpstreamPath:         r.UpstreamPath,
+		GrpcWeb:              r.GRPCWeb,
+		Rewrites:             rewrites,
+		Redirect:             r.Redirect,
+		RedirectCode:         int32(r.RedirectCode),
+		MaxInFlight:          int32(r.MaxInFlight),
+		QueueSize:            int32(r.QueueSize),
+		QueueTimeoutMs:       r.QueueTimeout.Milliseconds(),
+		AllowCIDRs:           r.AllowCIDRs,
+		DenyCIDRs:            r.DenyCIDRs,
+		MaxBodySize:          r.MaxBodySize,
+		MaxMultipartParts:    int32(r.MaxMultipartParts),
+		MaxMultipartPartSize: r.MaxMultipartPartSize,
+		MaxJSONDepth:         int32(r.MaxJSONDepth),
+		Schema:               r.Schema,
+		Audit:                r.Audit,
+		ForwardCredentials:   r.ForwardCredentials,
+	}
+}
+
+// removeStaleStaticRoutes forgets the routes that are no longer in the config file
+func (h *Handler) removeStaleStaticRoutes() {
+	for pathMethod, pr := range h.routes {
+		if pr.static && pr.generation != h.generation {
+			delete(h.routes, pathMethod)
+		}
+	}
+}
+
+// configFileVersion returns the path the config file resolves to and a hash of its content
+func configFileVersion(file string) string {
+	resolved, err := filepath.EvalSymlinks(file)
+	if err != nil {
+		return ""
+	}
+
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%x", resolved, sha256.Sum256(data))
+}
+
+// watchConfigFile triggers a reload of the config file on SIGHUP or when the file changes
+func (h *Handler) watchConfigFile() error {
+	logger := logruscomponent.MustReg(h.cReg).Logger()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	// Watch the directory, editors and configmaps replace the file instead of writing to it
+	if err := watcher.Add(filepath.Dir(h.configFile)); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+	h.configWatcher, h.configSignals = watcher, sigs
+
+	go func() {
+		// Configmaps swap a "..data" symlink, so any event in the directory may change what the file resolves to
+		version := configFileVersion(h.configFile)
+		for {
+			select {
+			case <-sigs:
+				logger.WithField("file", h.configFile).Debug("got SIGHUP")
+			case _, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				current := configFileVersion(h.configFile)
+				if current == "" || current == version {
+					continue
+				}
+				version = current
+				logger.WithField("file", h.configFile).Debug("config file changed")
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.WithField("file", h.configFile).Error(err)
+				continue
+			}
+
+			// Don't block if there's already a reload pending
+			select {
+			case h.reload <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"jochum.dev/jo-micro/router/cmd/microrouterd/config"
+	"jochum.dev/jo-micro/router/internal/proto/routerclientpb"
+)
+
+func TestStaticRoute(t *testing.T) {
+	tests := []struct {
+		name  string
+		route config.Route
+		want  *routerclientpb.RoutesReply_Route
+	}{
+		{
+			name:  "rpc",
+			route: config.Route{Method: "GET", Path: "/users", Endpoint: "UserService.List", Params: []string{"limit"}, AuthRequired: true},
+			want:  &routerclientpb.RoutesReply_Route{Method: "GET", Path: "/users", Endpoint: "UserService.List", Params: []string{"limit"}, AuthRequired: true},
+		},
+		{
+			name: "nested fields",
+			route: config.Route{
+				Method:       "GET",
+				Path:         "/users/:userId",
+				TypedParams:  []config.Param{{Name: "userId", Type: "int", Location: "path", Required: true, Default: "1"}},
+				Ratelimits:   []config.Ratelimit{{Key: "header:X-Tenant", Rates: []string{"10-S"}, Group: "tenants"}},
+				Rewrites:     []config.Rewrite{{Match: "/u/:userId"}},
+				QueueTimeout: 1500 * time.Millisecond,
+				RedirectCode: 301,
+			},
+			want: &routerclientpb.RoutesReply_Route{
+				Method:         "GET",
+				Path:           "/users/:userId",
+				TypedParams:    []*routerclientpb.RoutesReply_Param{{Name: "userId", Type: "int", Location: "path", Required: true, DefaultValue: "1"}},
+				Ratelimits:     []*routerclientpb.RoutesReply_Ratelimit{{Key: "header:X-Tenant", Rates: []string{"10-S"}, Group: "tenants"}},
+				Rewrites:       []*routerclientpb.RoutesReply_Rewrite{{Match: "/u/:userId"}},
+				QueueTimeoutMs: 1500,
+				RedirectCode:   301,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := staticRoute(tt.route); !proto.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchConfigFileSymlinkSwap(t *testing.T) {
+	// Lay out the directory like the kubelet does for a configmap
+	dir := t.TempDir()
+	for _, d := range []string{"..v1", "..v2"} {
+		if err := os.Mkdir(filepath.Join(dir, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, d, "router.yaml"), []byte("# "+d+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("..v1", filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("..data", "router.yaml"), filepath.Join(dir, "router.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	h := newTestHandler(t)
+	h.configFile = filepath.Join(dir, "router.yaml")
+	if err := h.watchConfigFile(); err != nil {
+		t.Fatal(err)
+	}
+	defer h.Stop()
+
+	// An event that doesn't change the file doesn't reload
+	if err := os.WriteFile(filepath.Join(dir, "unrelated"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-h.reload:
+		t.Fatal("reloaded without a change")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// Swap ..data like the kubelet does, the file name itself never gets an event
+	if err := os.Symlink("..v2", filepath.Join(dir, "..data_tmp")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-h.reload:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload after the symlink swap")
+	}
+}
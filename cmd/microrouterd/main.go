@@ -1,16 +1,18 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"time"
 
-	ginlogrus "github.com/toorop/gin-logrus"
 	"github.com/urfave/cli/v2"
 	"go-micro.dev/v4"
 	"go-micro.dev/v4/logger"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 
 	"github.com/gin-gonic/gin"
-	httpServer "github.com/go-micro/plugins/v4/server/http"
 	"jochum.dev/jo-micro/auth2"
 	jwtClient "jochum.dev/jo-micro/auth2/plugins/client/jwt"
 	jwtRouter "jochum.dev/jo-micro/auth2/plugins/router/jwt"
@@ -20,11 +22,13 @@ import (
 
 	"jochum.dev/jo-micro/router/cmd/microrouterd/config"
 	"jochum.dev/jo-micro/router/cmd/microrouterd/handler"
+	"jochum.dev/jo-micro/router/cmd/microrouterd/server"
 	"jochum.dev/jo-micro/router/internal/util"
 )
 
-func internalService(cReg *components.Registry, r *gin.Engine) {
-	routerHandler := handler.New()
+// internalService runs the service with the admin API until ctx is done, main stops it after the proxy
+func internalService(ctx context.Context, cReg *components.Registry, newEngine func() (*gin.Engine, error), routerHandler *handler.Handler, done chan struct{}) {
+	defer close(done)
 
 	auth2ClientReg := auth2.ClientAuthMustReg(cReg)
 
@@ -32,6 +36,10 @@ func internalService(cReg *components.Registry, r *gin.Engine) {
 		micro.Name(config.Name + "-internal"),
 		micro.Version(config.Version),
 		micro.WrapHandler(auth2ClientReg.WrapHandler()),
+		micro.Context(ctx),
+		micro.HandleSignal(false),
+		// Stop the handler before the server disconnects from the broker
+		micro.BeforeStop(routerHandler.Stop),
 		micro.Action(func(c *cli.Context) error {
 			// Start the components
 			if err := cReg.Init(c); err != nil {
@@ -40,7 +48,7 @@ func internalService(cReg *components.Registry, r *gin.Engine) {
 			}
 
 			// Initalize the Handler
-			if err := routerHandler.Init(cReg, r, c.Int("router_refresh"), c.String("router_ratelimiter_store_url")); err != nil {
+			if err := routerHandler.Init(cReg, newEngine, c); err != nil {
 				logger.Fatal(err)
 				return err
 			}
@@ -57,12 +65,6 @@ func internalService(cReg *components.Registry, r *gin.Engine) {
 		return
 	}
 
-	// Stop the handler
-	if err := routerHandler.Stop(); err != nil {
-		logger.Fatal(err)
-		return
-	}
-
 	// Stop the client/service auth plugin
 	if err := cReg.Stop(); err != nil {
 		logger.Fatal(err)
@@ -72,7 +74,7 @@ func internalService(cReg *components.Registry, r *gin.Engine) {
 
 func main() {
 	service := micro.NewService(
-		micro.Server(httpServer.NewServer()),
+		micro.Server(server.NewServer()),
 	)
 
 	cReg := components.New(service, "router", logruscomponent.New(), auth2.RouterAuthComponent())
@@ -87,7 +89,11 @@ func main() {
 	iAuth2ClientReg := auth2.ClientAuthMustReg(iCReg)
 	iAuth2ClientReg.Register(jwtClient.New())
 
-	var r *gin.Engine
+	var (
+		newEngine func() (*gin.Engine, error)
+		accessLog *handler.AccessLog
+	)
+	routerHandler := handler.New()
 
 	flags := components.FilterDuplicateFlags(iCReg.AppendFlags(cReg.AppendFlags([]cli.Flag{
 		// General
@@ -109,18 +115,251 @@ func main() {
 			EnvVars: []string{"MICRO_ROUTER_REFRESH"},
 			Value:   10,
 		},
+		&cli.IntFlag{
+			Name:    "router_refresh_concurrency",
+			Usage:   "How many services to ask for their routes at the same time",
+			EnvVars: []string{"MICRO_ROUTER_REFRESH_CONCURRENCY"},
+			Value:   8,
+		},
+		&cli.DurationFlag{
+			Name:    "router_refresh_timeout",
+			Usage:   "How long to wait for the routes of a service",
+			EnvVars: []string{"MICRO_ROUTER_REFRESH_TIMEOUT"},
+			Value:   5 * time.Second,
+		},
 		&cli.StringFlag{
 			Name:    "router_listen",
 			Usage:   "Router listen on",
 			EnvVars: []string{"MICRO_ROUTER_LISTEN"},
 			Value:   ":8080",
 		},
+		&cli.StringSliceFlag{
+			Name:    "router_trusted_proxies",
+			Usage:   "IPs/CIDRs of proxies allowed to set X-Forwarded-For, X-Real-IP, Forwarded and the PROXY protocol header, empty trusts nobody",
+			EnvVars: []string{"MICRO_ROUTER_TRUSTED_PROXIES"},
+		},
+		&cli.BoolFlag{
+			Name:    "router_proxy_protocol",
+			Usage:   "Accept the PROXY protocol from router_trusted_proxies",
+			EnvVars: []string{"MICRO_ROUTER_PROXY_PROTOCOL"},
+			Value:   false,
+		},
+		&cli.StringSliceFlag{
+			Name:    "router_allow_cidrs",
+			Usage:   "Only clients from these IPs/CIDRs may call any route, empty allows all",
+			EnvVars: []string{"MICRO_ROUTER_ALLOW_CIDRS"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "router_deny_cidrs",
+			Usage:   "Clients from these IPs/CIDRs get a 403 on all routes",
+			EnvVars: []string{"MICRO_ROUTER_DENY_CIDRS"},
+		},
+		&cli.Int64Flag{
+			Name:    "router_max_body_size",
+			Usage:   "Largest request body in bytes, 0 for no limit",
+			EnvVars: []string{"MICRO_ROUTER_MAX_BODY_SIZE"},
+			Value:   10 << 20,
+		},
+		&cli.IntFlag{
+			Name:    "router_max_multipart_parts",
+			Usage:   "Max number of parts of a multipart body, 0 for no limit",
+			EnvVars: []string{"MICRO_ROUTER_MAX_MULTIPART_PARTS"},
+			Value:   100,
+		},
+		&cli.Int64Flag{
+			Name:    "router_max_multipart_part_size",
+			Usage:   "Largest part of a multipart body in bytes, 0 for no limit",
+			EnvVars: []string{"MICRO_ROUTER_MAX_MULTIPART_PART_SIZE"},
+			Value:   0,
+		},
+		&cli.IntFlag{
+			Name:    "router_max_json_depth",
+			Usage:   "How deep objects and arrays in a JSON body may be nested, 0 for no limit",
+			EnvVars: []string{"MICRO_ROUTER_MAX_JSON_DEPTH"},
+			Value:   64,
+		},
+		&cli.StringFlag{
+			Name:    "router_error_format",
+			Usage:   "Format of error responses: envelope or problem (application/problem+json)",
+			EnvVars: []string{"MICRO_ROUTER_ERROR_FORMAT"},
+			Value:   "envelope",
+		},
+		&cli.StringSliceFlag{
+			Name:    "router_error_status",
+			Usage:   "Map codes of upstream errors to HTTP statuses, format: <code>=<status>, client errors default to 408=504, 500=502",
+			EnvVars: []string{"MICRO_ROUTER_ERROR_STATUS"},
+		},
+		&cli.StringFlag{
+			Name:    "router_accesslog",
+			Usage:   "Where to write the JSON access log: stdout, file, broker or off",
+			EnvVars: []string{"MICRO_ROUTER_ACCESSLOG"},
+			Value:   "stdout",
+		},
+		&cli.StringSliceFlag{
+			Name:    "router_accesslog_fields",
+			Usage:   "Fields of the access log, default all: time, method, path, query, route, service, endpoint, status, latency_ms, bytes, client_ip, user_agent, user_id, ratelimit, error",
+			EnvVars: []string{"MICRO_ROUTER_ACCESSLOG_FIELDS"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "router_accesslog_redact",
+			Usage:   "Fields of the access log to redact",
+			EnvVars: []string{"MICRO_ROUTER_ACCESSLOG_REDACT"},
+		},
+		&cli.Float64Flag{
+			Name:    "router_accesslog_sample",
+			Usage:   "Fraction of requests to log between 0 and 1, server errors are always logged",
+			EnvVars: []string{"MICRO_ROUTER_ACCESSLOG_SAMPLE"},
+			Value:   1,
+		},
+		&cli.StringFlag{
+			Name:    "router_accesslog_file",
+			Usage:   "Path of the access log file",
+			EnvVars: []string{"MICRO_ROUTER_ACCESSLOG_FILE"},
+			Value:   "access.log",
+		},
+		&cli.Int64Flag{
+			Name:    "router_accesslog_file_max_size",
+			Usage:   "Rotate the access log file when it gets larger than x MiB",
+			EnvVars: []string{"MICRO_ROUTER_ACCESSLOG_FILE_MAX_SIZE"},
+			Value:   100,
+		},
+		&cli.IntFlag{
+			Name:    "router_accesslog_file_max_backups",
+			Usage:   "How many rotated access log files to keep",
+			EnvVars: []string{"MICRO_ROUTER_ACCESSLOG_FILE_MAX_BACKUPS"},
+			Value:   5,
+		},
+		&cli.StringFlag{
+			Name:    "router_accesslog_topic",
+			Usage:   "Broker topic for the access log",
+			EnvVars: []string{"MICRO_ROUTER_ACCESSLOG_TOPIC"},
+			Value:   "router.accesslog",
+		},
+		&cli.IntFlag{
+			Name:    "router_accesslog_buffer",
+			Usage:   "How many access log entries to buffer for the broker before dropping them",
+			EnvVars: []string{"MICRO_ROUTER_ACCESSLOG_BUFFER"},
+			Value:   1024,
+		},
+		&cli.StringFlag{
+			Name:    "router_audit_topic",
+			Usage:   "Broker topic for the audit events of routes with router.Audit(), empty disables them",
+			EnvVars: []string{"MICRO_ROUTER_AUDIT_TOPIC"},
+			Value:   "router.audit",
+		},
+		&cli.IntFlag{
+			Name:    "router_audit_buffer",
+			Usage:   "How many audit events to buffer for the broker before dropping them",
+			EnvVars: []string{"MICRO_ROUTER_AUDIT_BUFFER"},
+			Value:   1024,
+		},
 		&cli.StringFlag{
 			Name:    "router_ratelimiter_store_url",
 			Usage:   "Ratelimiter store URL, for example redis://localhost:6379/0",
 			EnvVars: []string{"MICRO_ROUTER_RATELIMITER_STORE_URL"},
 			Value:   "memory://",
 		},
+		&cli.StringFlag{
+			Name:    "router_ratelimiter_on_error",
+			Usage:   "What to do while the redis ratelimiter store is down: memory (count locally), open (no limits) or closed (503)",
+			EnvVars: []string{"MICRO_ROUTER_RATELIMITER_ON_ERROR"},
+			Value:   "memory",
+		},
+		&cli.StringSliceFlag{
+			Name:    "router_ratelimit_default_clientip",
+			Usage:   "Client IP rate limits for routes that declare no limits, for example 10-S,100-M",
+			EnvVars: []string{"MICRO_ROUTER_RATELIMIT_DEFAULT_CLIENTIP"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "router_ratelimit_default_user",
+			Usage:   "User rate limits for routes that declare no limits",
+			EnvVars: []string{"MICRO_ROUTER_RATELIMIT_DEFAULT_USER"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "router_ratelimit_ceiling_clientip",
+			Usage:   "Client IP rate limits that apply to all routes on top of what they declare, they bound apikey/header/param limits whose values clients pick",
+			EnvVars: []string{"MICRO_ROUTER_RATELIMIT_CEILING_CLIENTIP"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "router_ratelimit_ceiling_user",
+			Usage:   "User rate limits that apply to all routes on top of what they declare",
+			EnvVars: []string{"MICRO_ROUTER_RATELIMIT_CEILING_USER"},
+		},
+		&cli.BoolFlag{
+			Name:    "router_ratelimit_legacy_headers",
+			Usage:   "Send the X-ClientIPRateLimit-*/X-UserRateLimit-* headers besides the RateLimit-* headers",
+			EnvVars: []string{"MICRO_ROUTER_RATELIMIT_LEGACY_HEADERS"},
+			Value:   false,
+		},
+		&cli.DurationFlag{
+			Name:    "router_drain_timeout",
+			Usage:   "How long to wait for requests in flight on shutdown",
+			EnvVars: []string{"MICRO_ROUTER_DRAIN_TIMEOUT"},
+			Value:   30 * time.Second,
+		},
+		&cli.IntFlag{
+			Name:    "router_breaker_failures",
+			Usage:   "Upstream failures (5xx) in a row after which requests to a service fail fast with 503, 0 disables the breaker",
+			EnvVars: []string{"MICRO_ROUTER_BREAKER_FAILURES"},
+			Value:   0,
+		},
+		&cli.DurationFlag{
+			Name:    "router_breaker_cooldown",
+			Usage:   "How long an open breaker fails fast before it lets a request through to try the service again",
+			EnvVars: []string{"MICRO_ROUTER_BREAKER_COOLDOWN"},
+			Value:   30 * time.Second,
+		},
+		&cli.DurationFlag{
+			Name:    "router_queue_timeout",
+			Usage:   "How long queued requests wait for a free slot of a concurrency limit without a queueTimeout",
+			EnvVars: []string{"MICRO_ROUTER_QUEUE_TIMEOUT"},
+			Value:   time.Second,
+		},
+		&cli.StringSliceFlag{
+			Name:    "router_service_concurrency",
+			Usage:   "Concurrency limits for all routes of a service, format: <service>=<maxInFlight>[:<queueSize>[:<queueTimeout>]]",
+			EnvVars: []string{"MICRO_ROUTER_SERVICE_CONCURRENCY"},
+		},
+		&cli.DurationFlag{
+			Name:    "router_deregister_delay",
+			Usage:   "How long to keep serving on shutdown after deregistering while /readyz answers 503, so load balancers and clients stop sending requests",
+			EnvVars: []string{"MICRO_ROUTER_DEREGISTER_DELAY"},
+			Value:   2 * time.Second,
+		},
+		&cli.BoolFlag{
+			Name:    "router_h2c",
+			Usage:   "Accept cleartext HTTP/2 for native gRPC clients",
+			EnvVars: []string{"MICRO_ROUTER_H2C"},
+			Value:   false,
+		},
+		&cli.StringSliceFlag{
+			Name:    "router_rewrite",
+			Usage:   "Rewrite rules \"<match>=><replace>\" applied before routing, match is a regexp when it starts with ^ else a template like /api/v2/users/:userId",
+			EnvVars: []string{"MICRO_ROUTER_REWRITE"},
+		},
+		&cli.StringFlag{
+			Name:    "router_maintenance_body",
+			Usage:   "JSON body for routes in maintenance mode",
+			EnvVars: []string{"MICRO_ROUTER_MAINTENANCE_BODY"},
+			Value:   `{"errors":[{"id":"SERVICE_UNAVAILABLE","message":"Down for maintenance, try again later"}]}`,
+		},
+		&cli.IntFlag{
+			Name:    "router_maintenance_retry_after",
+			Usage:   "Retry-After in seconds for routes in maintenance mode",
+			EnvVars: []string{"MICRO_ROUTER_MAINTENANCE_RETRY_AFTER"},
+			Value:   300,
+		},
+		&cli.StringFlag{
+			Name:    "router_state_topic",
+			Usage:   "Broker topic the instances share admin changes like the maintenance mode on, empty keeps them per instance",
+			EnvVars: []string{"MICRO_ROUTER_STATE_TOPIC"},
+			Value:   "router.state",
+		},
+		&cli.StringFlag{
+			Name:    "router_config_file",
+			Usage:   "YAML/JSON file with static routes, reloaded on SIGHUP or when it changes",
+			EnvVars: []string{"MICRO_ROUTER_CONFIG_FILE"},
+		},
 	})))
 
 	opts := []micro.Option{
@@ -141,18 +380,72 @@ func main() {
 			} else {
 				gin.SetMode(gin.ReleaseMode)
 			}
-			r = gin.New()
-			r.ForwardedByClientIP = true
+			if err := service.Server().Init(
+				server.DrainTimeout(c.Duration("router_drain_timeout")),
+				server.DeregisterDelay(c.Duration("router_deregister_delay")),
+				server.OnDraining(routerHandler.SetDraining),
+			); err != nil {
+				logger.Fatal(err)
+				return err
+			}
+
+			if c.Bool("router_proxy_protocol") {
+				if err := service.Server().Init(server.ProxyProtocol(c.StringSlice("router_trusted_proxies"))); err != nil {
+					logger.Fatal(err)
+					return err
+				}
+			}
+
+			var err error
+			accessLog, err = handler.NewAccessLog(c, service.Options().Broker, logruscomponent.MustReg(cReg).Logger())
+			if err != nil {
+				logger.Fatal(err)
+				return err
+			}
+
+			// Flush the access log after the last request, while the broker is still connected
+			if err := service.Server().Init(server.OnDrained(accessLog.Close)); err != nil {
+				logger.Fatal(err)
+				return err
+			}
 
-			// Add middlewares to gin
-			r.Use(ginlogrus.Logger(logruscomponent.MustReg(cReg).Logger()), gin.Recovery())
+			// The handler creates a new engine whenever routes get added
+			newEngine = func() (*gin.Engine, error) {
+				r := gin.New()
+				r.ForwardedByClientIP = true
+				if err := r.SetTrustedProxies(c.StringSlice("router_trusted_proxies")); err != nil {
+					return nil, err
+				}
 
-			r.NoRoute(func(c *gin.Context) {
-				c.JSON(http.StatusNotFound, gin.H{"errors": []gin.H{{"id": "NOT_FOUND", "message": "page not found"}}})
-			})
+				forwarded, err := handler.ForwardedMiddleware(c.StringSlice("router_trusted_proxies"))
+				if err != nil {
+					return nil, err
+				}
+
+				// Add middlewares to gin
+				r.Use(forwarded, accessLog.Middleware(), gin.Recovery())
+
+				r.NoRoute(routerHandler.NoRoute)
+
+				// Probes for kubernetes
+				r.GET("/healthz", routerHandler.Healthz)
+				r.GET("/readyz", routerHandler.Readyz)
+
+				return r, nil
+			}
+
+			// Fail early on invalid trusted proxies
+			if _, err := newEngine(); err != nil {
+				logger.Fatal(err)
+				return err
+			}
 
 			// Register gin with micro
-			if err := micro.RegisterHandler(service.Server(), r); err != nil {
+			var httpHandler http.Handler = routerHandler.RewriteHandler(routerHandler)
+			if c.Bool("router_h2c") {
+				httpHandler = h2c.NewHandler(httpHandler, &http2.Server{})
+			}
+			if err := micro.RegisterHandler(service.Server(), httpHandler); err != nil {
 				logger.Fatal(err)
 				return err
 			}
@@ -162,9 +455,11 @@ func main() {
 	}
 	service.Init(opts...)
 
-	go internalService(iCReg, r)
+	iCtx, iCancel := context.WithCancel(context.Background())
+	iDone := make(chan struct{})
+	go internalService(iCtx, iCReg, newEngine, routerHandler, iDone)
 
-	// Run server
+	// Run server, on a signal it deregisters, stops accepting connections, drains the requests in flight and flushes the access log
 	if err := service.Run(); err != nil {
 		logger.Fatal(err)
 		return
@@ -175,4 +470,8 @@ func main() {
 		logger.Fatal(err)
 		return
 	}
+
+	// The proxy needed the internal service until now
+	iCancel()
+	<-iDone
 }
@@ -0,0 +1,242 @@
+// Package server wraps the go-micro http server plugin to control the listener
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	httpServer "github.com/go-micro/plugins/v4/server/http"
+	"github.com/pires/go-proxyproto"
+	"go-micro.dev/v4/logger"
+	microServer "go-micro.dev/v4/server"
+)
+
+type proxyProtocolKey struct{}
+
+type drainTimeoutKey struct{}
+
+type deregisterDelayKey struct{}
+
+type onDrainedKey struct{}
+
+type onDrainingKey struct{}
+
+// defaultDrainTimeout is how long Stop waits for requests in flight if DrainTimeout isn't set
+const defaultDrainTimeout = 30 * time.Second
+
+// ProxyProtocol accepts PROXY protocol v1/v2 headers from connections of the trusted networks
+func ProxyProtocol(trusted []string) microServer.Option {
+	return func(o *microServer.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, proxyProtocolKey{}, trusted)
+	}
+}
+
+// DrainTimeout is how long Stop waits for requests in flight before it closes their connections
+func DrainTimeout(d time.Duration) microServer.Option {
+	return func(o *microServer.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, drainTimeoutKey{}, d)
+	}
+}
+
+// DeregisterDelay is how long Stop keeps serving after it deregistered so clients stop sending requests
+func DeregisterDelay(d time.Duration) microServer.Option {
+	return func(o *microServer.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, deregisterDelayKey{}, d)
+	}
+}
+
+// OnDraining runs fn when Stop starts, before it deregisters, so readiness probes can fail during the deregister delay
+func OnDraining(fn func()) microServer.Option {
+	return func(o *microServer.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, onDrainingKey{}, fn)
+	}
+}
+
+// OnDrained runs fn after the requests in flight finished, before the broker disconnects
+func OnDrained(fn func() error) microServer.Option {
+	return func(o *microServer.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, onDrainedKey{}, fn)
+	}
+}
+
+// registerer are the methods of the plugin that aren't part of server.Server
+type registerer interface {
+	Register() error
+	Deregister() error
+}
+
+// Server is the go-micro http server with its own listener, registration and the handlers stay with the plugin
+type Server struct {
+	microServer.Server
+
+	sync.Mutex
+	plugin  registerer
+	handler http.Handler
+	exit    chan chan error
+}
+
+func NewServer(opts ...microServer.Option) microServer.Server {
+	plugin := httpServer.NewServer(opts...)
+
+	return &Server{
+		Server: plugin,
+		plugin: plugin.(registerer),
+		exit:   make(chan chan error),
+	}
+}
+
+// Handle remembers the http.Handler to serve
+func (s *Server) Handle(handler microServer.Handler) error {
+	if h, ok := handler.Handler().(http.Handler); ok {
+		s.Lock()
+		s.handler = h
+		s.Unlock()
+	}
+
+	return s.Server.Handle(handler)
+}
+
+func (s *Server) listen(opts microServer.Options) (net.Listener, error) {
+	ln, err := net.Listen("tcp", opts.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	// The PROXY header comes before the TLS handshake
+	if trusted, ok := opts.Context.Value(proxyProtocolKey{}).([]string); ok && len(trusted) > 0 {
+		policy, err := proxyproto.LaxWhiteListPolicy(trusted)
+		if err != nil {
+			ln.Close()
+			return nil, err
+		}
+		ln = &proxyproto.Listener{Listener: ln, Policy: policy}
+	}
+
+	if opts.TLSConfig != nil {
+		ln = tls.NewListener(ln, opts.TLSConfig)
+	}
+
+	return ln, nil
+}
+
+func (s *Server) Start() error {
+	opts := s.Options()
+
+	s.Lock()
+	handler := s.handler
+	s.Unlock()
+	if handler == nil {
+		return errors.New("Server required http.Handler")
+	}
+
+	ln, err := s.listen(opts)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Listening on %s", ln.Addr().String())
+
+	if err := s.Init(microServer.Address(ln.Addr().String())); err != nil {
+		return err
+	}
+
+	if err := opts.Broker.Connect(); err != nil {
+		return err
+	}
+
+	if err := s.plugin.Register(); err != nil {
+		return err
+	}
+
+	srv := &http.Server{Handler: handler}
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			logger.Error("Server error: ", err)
+		}
+	}()
+
+	go func() {
+		t := new(time.Ticker)
+		if opts.RegisterInterval > time.Duration(0) {
+			t = time.NewTicker(opts.RegisterInterval)
+		}
+
+		var ch chan error
+
+	Loop:
+		for {
+			select {
+			case <-t.C:
+				if err := s.plugin.Register(); err != nil {
+					logger.Error("Server register error: ", err)
+				}
+			case ch = <-s.exit:
+				break Loop
+			}
+		}
+
+		drainTimeout := defaultDrainTimeout
+		if d, ok := opts.Context.Value(drainTimeoutKey{}).(time.Duration); ok {
+			drainTimeout = d
+		}
+
+		if fn, ok := opts.Context.Value(onDrainingKey{}).(func()); ok {
+			fn()
+		}
+
+		// Deregister, give clients time to notice, then stop accepting connections and wait for the requests in flight
+		if err := s.plugin.Deregister(); err != nil {
+			logger.Error("Server deregister error: ", err)
+		}
+
+		if d, ok := opts.Context.Value(deregisterDelayKey{}).(time.Duration); ok && d > 0 {
+			time.Sleep(d)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+		defer cancel()
+		err := srv.Shutdown(ctx)
+		if err == context.DeadlineExceeded {
+			logger.Warnf("Requests still in flight after %s, closing their connections", drainTimeout)
+			err = srv.Close()
+		}
+
+		if fn, ok := opts.Context.Value(onDrainedKey{}).(func() error); ok {
+			if err := fn(); err != nil {
+				logger.Error("Server on drained error: ", err)
+			}
+		}
+
+		opts.Broker.Disconnect()
+
+		ch <- err
+	}()
+
+	return nil
+}
+
+func (s *Server) Stop() error {
+	ch := make(chan error)
+	s.exit <- ch
+	return <-ch
+}
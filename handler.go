@@ -2,6 +2,7 @@ package router
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -91,6 +92,10 @@ func (h *Handler) Flags(r *components.Registry) []cli.Flag {
 }
 
 func (h *Handler) Health(context context.Context) error {
+	if !h.initialized {
+		return errors.New("not initialized")
+	}
+
 	return nil
 }
 
@@ -100,20 +105,69 @@ func (h *Handler) WrapHandlerFunc(ctx context.Context, req server.Request, rsp i
 
 func (h *Handler) Add(routes ...*Route) {
 	for _, r := range routes {
-		// NewRoute returns nil if no Endpoint has been specified, ignore these here
+		// NewRoute returns nil if a TypeRPC route has no Endpoint, ignore these here
 		if r == nil {
 			continue
 		}
 
+		endpoint := ""
+		if r.Endpoint != nil {
+			endpoint = util.ReflectFunctionName(r.Endpoint)
+		}
+
+		rewrites := make([]*routerclientpb.RoutesReply_Rewrite, len(r.Rewrites))
+		for idx, rw := range r.Rewrites {
+			rewrites[idx] = &routerclientpb.RoutesReply_Rewrite{Match: rw.Match, Replace: rw.Replace}
+		}
+
+		ratelimits := make([]*routerclientpb.RoutesReply_Ratelimit, len(r.Ratelimits))
+		for idx, rl := range r.Ratelimits {
+			ratelimits[idx] = &routerclientpb.RoutesReply_Ratelimit{Key: rl.Key, Rates: rl.Rates, Group: rl.Group}
+		}
+
+		typedParams := make([]*routerclientpb.RoutesReply_Param, len(r.TypedParams))
+		for idx, p := range r.TypedParams {
+			typedParams[idx] = &routerclientpb.RoutesReply_Param{
+				Name:         p.Name,
+				Type:         p.Type,
+				Location:     p.Location,
+				Required:     p.Required,
+				DefaultValue: p.Default,
+				Items:        p.Items,
+			}
+		}
+
 		h.routes = append(h.routes, &routerclientpb.RoutesReply_Route{
-			IsGlobal:          r.IsGlobal,
-			Method:            r.Method,
-			Path:              r.Path,
-			Endpoint:          util.ReflectFunctionName(r.Endpoint),
-			Params:            r.Params,
-			AuthRequired:      r.AuthRequired,
-			RatelimitClientIP: r.RatelimitClientIP,
-			RatelimitUser:     r.RatelimitUser,
+			IsGlobal:             r.IsGlobal,
+			Type:                 r.Type,
+			Method:               r.Method,
+			Path:                 r.Path,
+			Endpoint:             endpoint,
+			Params:               r.Params,
+			TypedParams:          typedParams,
+			ArrayStyle:           r.ArrayStyle,
+			AuthRequired:         r.AuthRequired,
+			RatelimitClientIP:    r.RatelimitClientIP,
+			RatelimitUser:        r.RatelimitUser,
+			Ratelimits:           ratelimits,
+			Upstream:             r.Upstream,
+			UpstreamPath:         r.UpstreamPath,
+			GrpcWeb:              r.GRPCWeb,
+			Rewrites:             rewrites,
+			Redirect:             r.Redirect,
+			RedirectCode:         int32(r.RedirectCode),
+			MaxInFlight:          int32(r.MaxInFlight),
+			QueueSize:            int32(r.QueueSize),
+			QueueTimeoutMs:       r.QueueTimeout.Milliseconds(),
+			AllowCIDRs:           r.AllowCIDRs,
+			DenyCIDRs:            r.DenyCIDRs,
+			MaxBodySize:          r.MaxBodySize,
+			MaxMultipartParts:    int32(r.MaxMultipartParts),
+			MaxMultipartPartSize: r.MaxMultipartPartSize,
+			MaxJSONDepth:         int32(r.MaxJSONDepth),
+			Schema:               r.Schema,
+			Audit:                r.Audit,
+			ForwardCredentials:   r.ForwardCredentials,
 		})
 	}
 }
@@ -76,6 +76,219 @@ func (x *RoutesReply) GetRoutes() []*RoutesReply_Route {
 	return nil
 }
 
+type RoutesReply_Rewrite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// match is a regexp when it starts with "^" else a template like "/api/v2/users/:userId"
+	Match string `protobuf:"bytes,1,opt,name=match,proto3" json:"match,omitempty"`
+	// replace is the new path, empty == the path of the route
+	Replace string `protobuf:"bytes,2,opt,name=replace,proto3" json:"replace,omitempty"`
+}
+
+func (x *RoutesReply_Rewrite) Reset() {
+	*x = RoutesReply_Rewrite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerclientpb_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RoutesReply_Rewrite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoutesReply_Rewrite) ProtoMessage() {}
+
+func (x *RoutesReply_Rewrite) ProtoReflect() protoreflect.Message {
+	mi := &file_routerclientpb_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoutesReply_Rewrite.ProtoReflect.Descriptor instead.
+func (*RoutesReply_Rewrite) Descriptor() ([]byte, []int) {
+	return file_routerclientpb_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *RoutesReply_Rewrite) GetMatch() string {
+	if x != nil {
+		return x.Match
+	}
+	return ""
+}
+
+func (x *RoutesReply_Rewrite) GetReplace() string {
+	if x != nil {
+		return x.Replace
+	}
+	return ""
+}
+
+type RoutesReply_Ratelimit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// key is one of "ip", "user", "apikey", "header:<name>", "param:<name>" or "claim:<name>"
+	Key   string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Rates []string `protobuf:"bytes,2,rep,name=rates,proto3" json:"rates,omitempty"`
+	// group shares the limit with all routes that use the same group, empty == per route
+	Group string `protobuf:"bytes,3,opt,name=group,proto3" json:"group,omitempty"`
+}
+
+func (x *RoutesReply_Ratelimit) Reset() {
+	*x = RoutesReply_Ratelimit{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerclientpb_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RoutesReply_Ratelimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoutesReply_Ratelimit) ProtoMessage() {}
+
+func (x *RoutesReply_Ratelimit) ProtoReflect() protoreflect.Message {
+	mi := &file_routerclientpb_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoutesReply_Ratelimit.ProtoReflect.Descriptor instead.
+func (*RoutesReply_Ratelimit) Descriptor() ([]byte, []int) {
+	return file_routerclientpb_proto_rawDescGZIP(), []int{0, 1}
+}
+
+func (x *RoutesReply_Ratelimit) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *RoutesReply_Ratelimit) GetRates() []string {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+func (x *RoutesReply_Ratelimit) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+type RoutesReply_Param struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// type is one of "string" (default when empty), "int", "float", "bool" or "array"
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// location is one of "path", "query" or "header", empty == path or query
+	Location string `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	Required bool   `protobuf:"varint,4,opt,name=required,proto3" json:"required,omitempty"`
+	// defaultValue is used when the request has no value, it gets coerced like a value from the request
+	DefaultValue string `protobuf:"bytes,5,opt,name=defaultValue,proto3" json:"defaultValue,omitempty"`
+	// items is the type of the values of an "array" param, empty == "string"
+	Items string `protobuf:"bytes,6,opt,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *RoutesReply_Param) Reset() {
+	*x = RoutesReply_Param{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerclientpb_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RoutesReply_Param) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoutesReply_Param) ProtoMessage() {}
+
+func (x *RoutesReply_Param) ProtoReflect() protoreflect.Message {
+	mi := &file_routerclientpb_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoutesReply_Param.ProtoReflect.Descriptor instead.
+func (*RoutesReply_Param) Descriptor() ([]byte, []int) {
+	return file_routerclientpb_proto_rawDescGZIP(), []int{0, 2}
+}
+
+func (x *RoutesReply_Param) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RoutesReply_Param) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *RoutesReply_Param) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *RoutesReply_Param) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *RoutesReply_Param) GetDefaultValue() string {
+	if x != nil {
+		return x.DefaultValue
+	}
+	return ""
+}
+
+func (x *RoutesReply_Param) GetItems() string {
+	if x != nil {
+		return x.Items
+	}
+	return ""
+}
+
 type RoutesReply_Route struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -90,12 +303,55 @@ type RoutesReply_Route struct {
 	AuthRequired      bool     `protobuf:"varint,6,opt,name=authRequired,proto3" json:"authRequired,omitempty"`
 	RatelimitClientIP []string `protobuf:"bytes,7,rep,name=ratelimitClientIP,proto3" json:"ratelimitClientIP,omitempty"`
 	RatelimitUser     []string `protobuf:"bytes,8,rep,name=ratelimitUser,proto3" json:"ratelimitUser,omitempty"`
+	// type is one of "rpc" (default when empty), "http", "redirect" or "maintenance"
+	Type string `protobuf:"bytes,9,opt,name=type,proto3" json:"type,omitempty"`
+	// upstream is the base URL of a plain HTTP service, empty == resolve the service from the registry
+	Upstream string `protobuf:"bytes,10,opt,name=upstream,proto3" json:"upstream,omitempty"`
+	// upstreamPath is the path on the upstream, ":name" and "*name" get replaced by route params
+	UpstreamPath string `protobuf:"bytes,11,opt,name=upstreamPath,proto3" json:"upstreamPath,omitempty"`
+	// grpcWeb=True == accept gRPC-Web and gRPC requests and call the endpoint with the protobuf codec
+	GrpcWeb bool `protobuf:"varint,12,opt,name=grpcWeb,proto3" json:"grpcWeb,omitempty"`
+	// rewrites are rules that rewrite requests to this route
+	Rewrites []*RoutesReply_Rewrite `protobuf:"bytes,13,rep,name=rewrites,proto3" json:"rewrites,omitempty"`
+	// redirect is the target of a "redirect" route, ":name" and "*name" get replaced by route params
+	Redirect string `protobuf:"bytes,14,opt,name=redirect,proto3" json:"redirect,omitempty"`
+	// redirectCode is the HTTP status of a "redirect" route, 0 == 302
+	RedirectCode int32                    `protobuf:"varint,15,opt,name=redirectCode,proto3" json:"redirectCode,omitempty"`
+	Ratelimits   []*RoutesReply_Ratelimit `protobuf:"bytes,16,rep,name=ratelimits,proto3" json:"ratelimits,omitempty"`
+	// maxInFlight limits the concurrent requests to this route, 0 == no limit
+	MaxInFlight int32 `protobuf:"varint,17,opt,name=maxInFlight,proto3" json:"maxInFlight,omitempty"`
+	// queueSize is the number of requests that wait for a free slot when maxInFlight is reached
+	QueueSize int32 `protobuf:"varint,18,opt,name=queueSize,proto3" json:"queueSize,omitempty"`
+	// queueTimeoutMs is how long a queued request waits, 0 == the routers default
+	QueueTimeoutMs int64 `protobuf:"varint,19,opt,name=queueTimeoutMs,proto3" json:"queueTimeoutMs,omitempty"`
+	// allowCIDRs are the only client networks allowed to call this route, empty == all
+	AllowCIDRs []string `protobuf:"bytes,20,rep,name=allowCIDRs,proto3" json:"allowCIDRs,omitempty"`
+	// denyCIDRs are client networks that get a 403, they win over allowCIDRs
+	DenyCIDRs []string `protobuf:"bytes,21,rep,name=denyCIDRs,proto3" json:"denyCIDRs,omitempty"`
+	// maxBodySize is the largest body in bytes, 0 == the limit of the router
+	MaxBodySize int64 `protobuf:"varint,22,opt,name=maxBodySize,proto3" json:"maxBodySize,omitempty"`
+	// maxMultipartParts is the max number of parts of a multipart body, 0 == the limit of the router
+	MaxMultipartParts int32 `protobuf:"varint,23,opt,name=maxMultipartParts,proto3" json:"maxMultipartParts,omitempty"`
+	// maxMultipartPartSize is the largest part in bytes, 0 == the limit of the router
+	MaxMultipartPartSize int64 `protobuf:"varint,24,opt,name=maxMultipartPartSize,proto3" json:"maxMultipartPartSize,omitempty"`
+	// maxJSONDepth is how deep objects and arrays in a JSON body may be nested, 0 == the limit of the router
+	MaxJSONDepth int32 `protobuf:"varint,25,opt,name=maxJSONDepth,proto3" json:"maxJSONDepth,omitempty"`
+	// schema is a JSON Schema the request (params and body) must match, empty == no validation
+	Schema string `protobuf:"bytes,26,opt,name=schema,proto3" json:"schema,omitempty"`
+	// typedParams are coerced to their type before they get added to the request
+	TypedParams []*RoutesReply_Param `protobuf:"bytes,27,rep,name=typedParams,proto3" json:"typedParams,omitempty"`
+	// arrayStyle is how "array" query params are sent: "repeat" (default when empty), "comma" or "brackets"
+	ArrayStyle string `protobuf:"bytes,28,opt,name=arrayStyle,proto3" json:"arrayStyle,omitempty"`
+	// audit=True == publish an audit event for every request
+	Audit bool `protobuf:"varint,29,opt,name=audit,proto3" json:"audit,omitempty"`
+	// forwardCredentials=True == pass the Authorization and Cookie headers to the HTTP upstream
+	ForwardCredentials bool `protobuf:"varint,30,opt,name=forwardCredentials,proto3" json:"forwardCredentials,omitempty"`
 }
 
 func (x *RoutesReply_Route) Reset() {
 	*x = RoutesReply_Route{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerclientpb_proto_msgTypes[1]
+		mi := &file_routerclientpb_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -108,7 +364,7 @@ func (x *RoutesReply_Route) String() string {
 func (*RoutesReply_Route) ProtoMessage() {}
 
 func (x *RoutesReply_Route) ProtoReflect() protoreflect.Message {
-	mi := &file_routerclientpb_proto_msgTypes[1]
+	mi := &file_routerclientpb_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -121,7 +377,7 @@ func (x *RoutesReply_Route) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoutesReply_Route.ProtoReflect.Descriptor instead.
 func (*RoutesReply_Route) Descriptor() ([]byte, []int) {
-	return file_routerclientpb_proto_rawDescGZIP(), []int{0, 0}
+	return file_routerclientpb_proto_rawDescGZIP(), []int{0, 3}
 }
 
 func (x *RoutesReply_Route) GetIsGlobal() bool {
@@ -180,6 +436,160 @@ func (x *RoutesReply_Route) GetRatelimitUser() []string {
 	return nil
 }
 
+func (x *RoutesReply_Route) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *RoutesReply_Route) GetUpstream() string {
+	if x != nil {
+		return x.Upstream
+	}
+	return ""
+}
+
+func (x *RoutesReply_Route) GetUpstreamPath() string {
+	if x != nil {
+		return x.UpstreamPath
+	}
+	return ""
+}
+
+func (x *RoutesReply_Route) GetGrpcWeb() bool {
+	if x != nil {
+		return x.GrpcWeb
+	}
+	return false
+}
+
+func (x *RoutesReply_Route) GetRewrites() []*RoutesReply_Rewrite {
+	if x != nil {
+		return x.Rewrites
+	}
+	return nil
+}
+
+func (x *RoutesReply_Route) GetRedirect() string {
+	if x != nil {
+		return x.Redirect
+	}
+	return ""
+}
+
+func (x *RoutesReply_Route) GetRedirectCode() int32 {
+	if x != nil {
+		return x.RedirectCode
+	}
+	return 0
+}
+
+func (x *RoutesReply_Route) GetRatelimits() []*RoutesReply_Ratelimit {
+	if x != nil {
+		return x.Ratelimits
+	}
+	return nil
+}
+
+func (x *RoutesReply_Route) GetMaxInFlight() int32 {
+	if x != nil {
+		return x.MaxInFlight
+	}
+	return 0
+}
+
+func (x *RoutesReply_Route) GetQueueSize() int32 {
+	if x != nil {
+		return x.QueueSize
+	}
+	return 0
+}
+
+func (x *RoutesReply_Route) GetQueueTimeoutMs() int64 {
+	if x != nil {
+		return x.QueueTimeoutMs
+	}
+	return 0
+}
+
+func (x *RoutesReply_Route) GetAllowCIDRs() []string {
+	if x != nil {
+		return x.AllowCIDRs
+	}
+	return nil
+}
+
+func (x *RoutesReply_Route) GetDenyCIDRs() []string {
+	if x != nil {
+		return x.DenyCIDRs
+	}
+	return nil
+}
+
+func (x *RoutesReply_Route) GetMaxBodySize() int64 {
+	if x != nil {
+		return x.MaxBodySize
+	}
+	return 0
+}
+
+func (x *RoutesReply_Route) GetMaxMultipartParts() int32 {
+	if x != nil {
+		return x.MaxMultipartParts
+	}
+	return 0
+}
+
+func (x *RoutesReply_Route) GetMaxMultipartPartSize() int64 {
+	if x != nil {
+		return x.MaxMultipartPartSize
+	}
+	return 0
+}
+
+func (x *RoutesReply_Route) GetMaxJSONDepth() int32 {
+	if x != nil {
+		return x.MaxJSONDepth
+	}
+	return 0
+}
+
+func (x *RoutesReply_Route) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+func (x *RoutesReply_Route) GetTypedParams() []*RoutesReply_Param {
+	if x != nil {
+		return x.TypedParams
+	}
+	return nil
+}
+
+func (x *RoutesReply_Route) GetArrayStyle() string {
+	if x != nil {
+		return x.ArrayStyle
+	}
+	return ""
+}
+
+func (x *RoutesReply_Route) GetAudit() bool {
+	if x != nil {
+		return x.Audit
+	}
+	return false
+}
+
+func (x *RoutesReply_Route) GetForwardCredentials() bool {
+	if x != nil {
+		return x.ForwardCredentials
+	}
+	return false
+}
+
 var File_routerclientpb_proto protoreflect.FileDescriptor
 
 var file_routerclientpb_proto_rawDesc = []byte{
@@ -187,39 +597,110 @@ var file_routerclientpb_proto_rawDesc = []byte{
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x63, 0x6c,
 	0x69, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x22, 0xe4, 0x02, 0x0a, 0x0b, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x52, 0x65,
+	0x6f, 0x74, 0x6f, 0x22, 0xd5, 0x0b, 0x0a, 0x0b, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x52, 0x65,
 	0x70, 0x6c, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x55, 0x52, 0x49,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x55, 0x52,
 	0x49, 0x12, 0x39, 0x0a, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
 	0x0b, 0x32, 0x21, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
 	0x70, 0x62, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x52,
-	0x6f, 0x75, 0x74, 0x65, 0x52, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x1a, 0xfb, 0x01, 0x0a,
-	0x05, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x73, 0x47, 0x6c, 0x6f, 0x62,
-	0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x47, 0x6c, 0x6f, 0x62,
-	0x61, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61,
-	0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x1a,
-	0x0a, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61,
-	0x72, 0x61, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61,
-	0x6d, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x75, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72,
-	0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x61, 0x75, 0x74, 0x68, 0x52, 0x65,
-	0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x2c, 0x0a, 0x11, 0x72, 0x61, 0x74, 0x65, 0x6c, 0x69,
-	0x6d, 0x69, 0x74, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x50, 0x18, 0x07, 0x20, 0x03, 0x28,
-	0x09, 0x52, 0x11, 0x72, 0x61, 0x74, 0x65, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x43, 0x6c, 0x69, 0x65,
-	0x6e, 0x74, 0x49, 0x50, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x61, 0x74, 0x65, 0x6c, 0x69, 0x6d, 0x69,
-	0x74, 0x55, 0x73, 0x65, 0x72, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x61, 0x74,
-	0x65, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x55, 0x73, 0x65, 0x72, 0x32, 0x56, 0x0a, 0x13, 0x52, 0x6f,
-	0x75, 0x74, 0x65, 0x72, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x12, 0x3f, 0x0a, 0x06, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
-	0x70, 0x74, 0x79, 0x1a, 0x1b, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x63, 0x6c, 0x69, 0x65,
-	0x6e, 0x74, 0x70, 0x62, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79,
-	0x22, 0x00, 0x42, 0x40, 0x5a, 0x3e, 0x6a, 0x6f, 0x63, 0x68, 0x75, 0x6d, 0x2e, 0x64, 0x65, 0x76,
-	0x2f, 0x6a, 0x6f, 0x2d, 0x6d, 0x69, 0x63, 0x72, 0x6f, 0x2f, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
-	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x63, 0x6c, 0x69,
-	0x65, 0x6e, 0x74, 0x70, 0x62, 0x3b, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x63, 0x6c, 0x69, 0x65,
-	0x6e, 0x74, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6f, 0x75, 0x74, 0x65, 0x52, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x1a, 0x39, 0x0a, 0x07,
+	0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x18, 0x0a,
+	0x07, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x1a, 0x49, 0x0a, 0x09, 0x52, 0x61, 0x74, 0x65, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x61, 0x74, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x72, 0x61, 0x74, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x1a, 0xa1, 0x01, 0x0a, 0x05, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x22, 0x0a, 0x0c,
+	0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x1a, 0xc2, 0x08, 0x0a, 0x05, 0x52, 0x6f, 0x75, 0x74, 0x65,
+	0x12, 0x1a, 0x0a, 0x08, 0x69, 0x73, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x12, 0x16, 0x0a, 0x06,
+	0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65,
+	0x74, 0x68, 0x6f, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x22, 0x0a, 0x0c,
+	0x61, 0x75, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0c, 0x61, 0x75, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64,
+	0x12, 0x2c, 0x0a, 0x11, 0x72, 0x61, 0x74, 0x65, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x43, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x49, 0x50, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x11, 0x72, 0x61, 0x74,
+	0x65, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x50, 0x12, 0x24,
+	0x0a, 0x0d, 0x72, 0x61, 0x74, 0x65, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x55, 0x73, 0x65, 0x72, 0x18,
+	0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x61, 0x74, 0x65, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x55, 0x73, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x70, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x70, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x12, 0x22, 0x0a, 0x0c, 0x75, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x50, 0x61, 0x74, 0x68, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x75, 0x70, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x50, 0x61, 0x74, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x72, 0x70, 0x63,
+	0x57, 0x65, 0x62, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x67, 0x72, 0x70, 0x63, 0x57,
+	0x65, 0x62, 0x12, 0x3f, 0x0a, 0x08, 0x72, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x73, 0x18, 0x0d,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x2e, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x52, 0x08, 0x72, 0x65, 0x77, 0x72, 0x69,
+	0x74, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x12,
+	0x22, 0x0a, 0x0c, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x43,
+	0x6f, 0x64, 0x65, 0x12, 0x45, 0x0a, 0x0a, 0x72, 0x61, 0x74, 0x65, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x0a,
+	0x72, 0x61, 0x74, 0x65, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x6d, 0x61,
+	0x78, 0x49, 0x6e, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0b, 0x6d, 0x61, 0x78, 0x49, 0x6e, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1c, 0x0a, 0x09,
+	0x71, 0x75, 0x65, 0x75, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x09, 0x71, 0x75, 0x65, 0x75, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x71, 0x75,
+	0x65, 0x75, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x4d, 0x73, 0x18, 0x13, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0e, 0x71, 0x75, 0x65, 0x75, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x4d, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x43, 0x49, 0x44, 0x52, 0x73,
+	0x18, 0x14, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x43, 0x49, 0x44,
+	0x52, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x65, 0x6e, 0x79, 0x43, 0x49, 0x44, 0x52, 0x73, 0x18,
+	0x15, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x64, 0x65, 0x6e, 0x79, 0x43, 0x49, 0x44, 0x52, 0x73,
+	0x12, 0x20, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x42, 0x6f, 0x64, 0x79, 0x53, 0x69, 0x7a, 0x65, 0x18,
+	0x16, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x42, 0x6f, 0x64, 0x79, 0x53, 0x69,
+	0x7a, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x6d, 0x61, 0x78, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61,
+	0x72, 0x74, 0x50, 0x61, 0x72, 0x74, 0x73, 0x18, 0x17, 0x20, 0x01, 0x28, 0x05, 0x52, 0x11, 0x6d,
+	0x61, 0x78, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x72, 0x74, 0x50, 0x61, 0x72, 0x74, 0x73,
+	0x12, 0x32, 0x0a, 0x14, 0x6d, 0x61, 0x78, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x72, 0x74,
+	0x50, 0x61, 0x72, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x18, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14,
+	0x6d, 0x61, 0x78, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x72, 0x74, 0x50, 0x61, 0x72, 0x74,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x4a, 0x53, 0x4f, 0x4e, 0x44,
+	0x65, 0x70, 0x74, 0x68, 0x18, 0x19, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x4a,
+	0x53, 0x4f, 0x4e, 0x44, 0x65, 0x70, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x63, 0x68, 0x65,
+	0x6d, 0x61, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61,
+	0x12, 0x43, 0x0a, 0x0b, 0x74, 0x79, 0x70, 0x65, 0x64, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18,
+	0x1b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x63, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x52, 0x0b, 0x74, 0x79, 0x70, 0x65, 0x64, 0x50,
+	0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x72, 0x72, 0x61, 0x79, 0x53, 0x74,
+	0x79, 0x6c, 0x65, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x72, 0x72, 0x61, 0x79,
+	0x53, 0x74, 0x79, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x75, 0x64, 0x69, 0x74, 0x18, 0x1d,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x61, 0x75, 0x64, 0x69, 0x74, 0x12, 0x2e, 0x0a, 0x12, 0x66,
+	0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c,
+	0x73, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64,
+	0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x32, 0x56, 0x0a, 0x13, 0x52,
+	0x6f, 0x75, 0x74, 0x65, 0x72, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x3f, 0x0a, 0x06, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x12, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1b, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x22, 0x00, 0x42, 0x40, 0x5a, 0x3e, 0x6a, 0x6f, 0x63, 0x68, 0x75, 0x6d, 0x2e, 0x64, 0x65,
+	0x76, 0x2f, 0x6a, 0x6f, 0x2d, 0x6d, 0x69, 0x63, 0x72, 0x6f, 0x2f, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x63, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x3b, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -234,21 +715,27 @@ func file_routerclientpb_proto_rawDescGZIP() []byte {
 	return file_routerclientpb_proto_rawDescData
 }
 
-var file_routerclientpb_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_routerclientpb_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_routerclientpb_proto_goTypes = []interface{}{
-	(*RoutesReply)(nil),       // 0: routerclientpb.RoutesReply
-	(*RoutesReply_Route)(nil), // 1: routerclientpb.RoutesReply.Route
-	(*emptypb.Empty)(nil),     // 2: google.protobuf.Empty
+	(*RoutesReply)(nil),           // 0: routerclientpb.RoutesReply
+	(*RoutesReply_Rewrite)(nil),   // 1: routerclientpb.RoutesReply.Rewrite
+	(*RoutesReply_Ratelimit)(nil), // 2: routerclientpb.RoutesReply.Ratelimit
+	(*RoutesReply_Param)(nil),     // 3: routerclientpb.RoutesReply.Param
+	(*RoutesReply_Route)(nil),     // 4: routerclientpb.RoutesReply.Route
+	(*emptypb.Empty)(nil),         // 5: google.protobuf.Empty
 }
 var file_routerclientpb_proto_depIdxs = []int32{
-	1, // 0: routerclientpb.RoutesReply.routes:type_name -> routerclientpb.RoutesReply.Route
-	2, // 1: routerclientpb.RouterClientService.Routes:input_type -> google.protobuf.Empty
-	0, // 2: routerclientpb.RouterClientService.Routes:output_type -> routerclientpb.RoutesReply
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	4, // 0: routerclientpb.RoutesReply.routes:type_name -> routerclientpb.RoutesReply.Route
+	1, // 1: routerclientpb.RoutesReply.Route.rewrites:type_name -> routerclientpb.RoutesReply.Rewrite
+	2, // 2: routerclientpb.RoutesReply.Route.ratelimits:type_name -> routerclientpb.RoutesReply.Ratelimit
+	3, // 3: routerclientpb.RoutesReply.Route.typedParams:type_name -> routerclientpb.RoutesReply.Param
+	5, // 4: routerclientpb.RouterClientService.Routes:input_type -> google.protobuf.Empty
+	0, // 5: routerclientpb.RouterClientService.Routes:output_type -> routerclientpb.RoutesReply
+	5, // [5:6] is the sub-list for method output_type
+	4, // [4:5] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_routerclientpb_proto_init() }
@@ -270,6 +757,42 @@ func file_routerclientpb_proto_init() {
 			}
 		}
 		file_routerclientpb_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RoutesReply_Rewrite); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerclientpb_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RoutesReply_Ratelimit); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerclientpb_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RoutesReply_Param); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerclientpb_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RoutesReply_Route); i {
 			case 0:
 				return &v.state
@@ -288,7 +811,7 @@ func file_routerclientpb_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_routerclientpb_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
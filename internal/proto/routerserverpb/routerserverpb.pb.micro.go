@@ -38,6 +38,14 @@ func NewRouterServerServiceEndpoints() []*api.Endpoint {
 
 type RouterServerService interface {
 	Routes(ctx context.Context, in *emptypb.Empty, opts ...client.CallOption) (*RoutesReply, error)
+	RoutesV2(ctx context.Context, in *RoutesV2Request, opts ...client.CallOption) (*RoutesV2Reply, error)
+	Maintenance(ctx context.Context, in *MaintenanceRequest, opts ...client.CallOption) (*MaintenanceReply, error)
+	Services(ctx context.Context, in *emptypb.Empty, opts ...client.CallOption) (*ServicesReply, error)
+	Refresh(ctx context.Context, in *emptypb.Empty, opts ...client.CallOption) (*emptypb.Empty, error)
+	RouteState(ctx context.Context, in *RouteStateRequest, opts ...client.CallOption) (*RouteStateReply, error)
+	SetRouteEnabled(ctx context.Context, in *SetRouteEnabledRequest, opts ...client.CallOption) (*RouteStateReply, error)
+	ResetRatelimit(ctx context.Context, in *RouteStateRequest, opts ...client.CallOption) (*RouteStateReply, error)
+	Config(ctx context.Context, in *emptypb.Empty, opts ...client.CallOption) (*ConfigReply, error)
 }
 
 type routerServerService struct {
@@ -62,15 +70,111 @@ func (c *routerServerService) Routes(ctx context.Context, in *emptypb.Empty, opt
 	return out, nil
 }
 
+func (c *routerServerService) RoutesV2(ctx context.Context, in *RoutesV2Request, opts ...client.CallOption) (*RoutesV2Reply, error) {
+	req := c.c.NewRequest(c.name, "RouterServerService.RoutesV2", in)
+	out := new(RoutesV2Reply)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerServerService) Maintenance(ctx context.Context, in *MaintenanceRequest, opts ...client.CallOption) (*MaintenanceReply, error) {
+	req := c.c.NewRequest(c.name, "RouterServerService.Maintenance", in)
+	out := new(MaintenanceReply)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerServerService) Services(ctx context.Context, in *emptypb.Empty, opts ...client.CallOption) (*ServicesReply, error) {
+	req := c.c.NewRequest(c.name, "RouterServerService.Services", in)
+	out := new(ServicesReply)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerServerService) Refresh(ctx context.Context, in *emptypb.Empty, opts ...client.CallOption) (*emptypb.Empty, error) {
+	req := c.c.NewRequest(c.name, "RouterServerService.Refresh", in)
+	out := new(emptypb.Empty)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerServerService) RouteState(ctx context.Context, in *RouteStateRequest, opts ...client.CallOption) (*RouteStateReply, error) {
+	req := c.c.NewRequest(c.name, "RouterServerService.RouteState", in)
+	out := new(RouteStateReply)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerServerService) SetRouteEnabled(ctx context.Context, in *SetRouteEnabledRequest, opts ...client.CallOption) (*RouteStateReply, error) {
+	req := c.c.NewRequest(c.name, "RouterServerService.SetRouteEnabled", in)
+	out := new(RouteStateReply)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerServerService) ResetRatelimit(ctx context.Context, in *RouteStateRequest, opts ...client.CallOption) (*RouteStateReply, error) {
+	req := c.c.NewRequest(c.name, "RouterServerService.ResetRatelimit", in)
+	out := new(RouteStateReply)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerServerService) Config(ctx context.Context, in *emptypb.Empty, opts ...client.CallOption) (*ConfigReply, error) {
+	req := c.c.NewRequest(c.name, "RouterServerService.Config", in)
+	out := new(ConfigReply)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for RouterServerService service
 
 type RouterServerServiceHandler interface {
 	Routes(context.Context, *emptypb.Empty, *RoutesReply) error
+	RoutesV2(context.Context, *RoutesV2Request, *RoutesV2Reply) error
+	Maintenance(context.Context, *MaintenanceRequest, *MaintenanceReply) error
+	Services(context.Context, *emptypb.Empty, *ServicesReply) error
+	Refresh(context.Context, *emptypb.Empty, *emptypb.Empty) error
+	RouteState(context.Context, *RouteStateRequest, *RouteStateReply) error
+	SetRouteEnabled(context.Context, *SetRouteEnabledRequest, *RouteStateReply) error
+	ResetRatelimit(context.Context, *RouteStateRequest, *RouteStateReply) error
+	Config(context.Context, *emptypb.Empty, *ConfigReply) error
 }
 
 func RegisterRouterServerServiceHandler(s server.Server, hdlr RouterServerServiceHandler, opts ...server.HandlerOption) error {
 	type routerServerService interface {
 		Routes(ctx context.Context, in *emptypb.Empty, out *RoutesReply) error
+		RoutesV2(ctx context.Context, in *RoutesV2Request, out *RoutesV2Reply) error
+		Maintenance(ctx context.Context, in *MaintenanceRequest, out *MaintenanceReply) error
+		Services(ctx context.Context, in *emptypb.Empty, out *ServicesReply) error
+		Refresh(ctx context.Context, in *emptypb.Empty, out *emptypb.Empty) error
+		RouteState(ctx context.Context, in *RouteStateRequest, out *RouteStateReply) error
+		SetRouteEnabled(ctx context.Context, in *SetRouteEnabledRequest, out *RouteStateReply) error
+		ResetRatelimit(ctx context.Context, in *RouteStateRequest, out *RouteStateReply) error
+		Config(ctx context.Context, in *emptypb.Empty, out *ConfigReply) error
 	}
 	type RouterServerService struct {
 		routerServerService
@@ -86,3 +190,35 @@ type routerServerServiceHandler struct {
 func (h *routerServerServiceHandler) Routes(ctx context.Context, in *emptypb.Empty, out *RoutesReply) error {
 	return h.RouterServerServiceHandler.Routes(ctx, in, out)
 }
+
+func (h *routerServerServiceHandler) RoutesV2(ctx context.Context, in *RoutesV2Request, out *RoutesV2Reply) error {
+	return h.RouterServerServiceHandler.RoutesV2(ctx, in, out)
+}
+
+func (h *routerServerServiceHandler) Maintenance(ctx context.Context, in *MaintenanceRequest, out *MaintenanceReply) error {
+	return h.RouterServerServiceHandler.Maintenance(ctx, in, out)
+}
+
+func (h *routerServerServiceHandler) Services(ctx context.Context, in *emptypb.Empty, out *ServicesReply) error {
+	return h.RouterServerServiceHandler.Services(ctx, in, out)
+}
+
+func (h *routerServerServiceHandler) Refresh(ctx context.Context, in *emptypb.Empty, out *emptypb.Empty) error {
+	return h.RouterServerServiceHandler.Refresh(ctx, in, out)
+}
+
+func (h *routerServerServiceHandler) RouteState(ctx context.Context, in *RouteStateRequest, out *RouteStateReply) error {
+	return h.RouterServerServiceHandler.RouteState(ctx, in, out)
+}
+
+func (h *routerServerServiceHandler) SetRouteEnabled(ctx context.Context, in *SetRouteEnabledRequest, out *RouteStateReply) error {
+	return h.RouterServerServiceHandler.SetRouteEnabled(ctx, in, out)
+}
+
+func (h *routerServerServiceHandler) ResetRatelimit(ctx context.Context, in *RouteStateRequest, out *RouteStateReply) error {
+	return h.RouterServerServiceHandler.ResetRatelimit(ctx, in, out)
+}
+
+func (h *routerServerServiceHandler) Config(ctx context.Context, in *emptypb.Empty, out *ConfigReply) error {
+	return h.RouterServerServiceHandler.Config(ctx, in, out)
+}
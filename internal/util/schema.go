@@ -0,0 +1,96 @@
+package util
+
+import (
+	"encoding/json"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ProtoSchema returns a JSON Schema for the JSON form of msg as the go-micro json codec (jsonpb) accepts it
+func ProtoSchema(msg proto.Message) (string, error) {
+	schema := messageSchema(msg.ProtoReflect().Descriptor(), map[protoreflect.FullName]bool{})
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func messageSchema(md protoreflect.MessageDescriptor, seen map[protoreflect.FullName]bool) map[string]interface{} {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp", "google.protobuf.Duration", "google.protobuf.FieldMask":
+		return map[string]interface{}{"type": "string"}
+	case "google.protobuf.Struct", "google.protobuf.Any", "google.protobuf.Empty":
+		return map[string]interface{}{"type": "object"}
+	case "google.protobuf.ListValue":
+		return map[string]interface{}{"type": "array"}
+	case "google.protobuf.Value":
+		return map[string]interface{}{}
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue", "google.protobuf.Int64Value",
+		"google.protobuf.UInt64Value", "google.protobuf.Int32Value", "google.protobuf.UInt32Value",
+		"google.protobuf.BoolValue", "google.protobuf.StringValue", "google.protobuf.BytesValue":
+		return fieldSchema(md.Fields().ByName("value"), seen)
+	}
+
+	// Recursive messages end in a plain object
+	if seen[md.FullName()] {
+		return map[string]interface{}{"type": "object"}
+	}
+	seen[md.FullName()] = true
+	defer delete(seen, md.FullName())
+
+	properties := map[string]interface{}{}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+
+		schema := fieldSchema(fd, seen)
+		if fd.IsMap() {
+			schema = map[string]interface{}{"type": "object", "additionalProperties": fieldSchema(fd.MapValue(), seen)}
+		} else if fd.IsList() {
+			schema = map[string]interface{}{"type": "array", "items": schema}
+		}
+
+		// jsonpb accepts the lowerCamelCase and the original name
+		properties[fd.JSONName()] = schema
+		properties[string(fd.Name())] = schema
+	}
+
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// fieldSchema returns the schema of a single value of fd
+func fieldSchema(fd protoreflect.FieldDescriptor, seen map[protoreflect.FullName]bool) map[string]interface{} {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	case protoreflect.StringKind:
+		return map[string]interface{}{"type": "string"}
+	case protoreflect.BytesKind:
+		return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		// NaN and Infinity are strings
+		return map[string]interface{}{"type": []string{"number", "string"}}
+	case protoreflect.EnumKind:
+		values := []interface{}{}
+		evs := fd.Enum().Values()
+		for i := 0; i < evs.Len(); i++ {
+			values = append(values, string(evs.Get(i).Name()), int(evs.Get(i).Number()))
+		}
+		return map[string]interface{}{"enum": values}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		schema := messageSchema(fd.Message(), seen)
+		return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+	default:
+		// All integers, jsonpb accepts them quoted
+		return map[string]interface{}{"type": []string{"integer", "string"}, "pattern": "^-?[0-9]+$"}
+	}
+}
@@ -0,0 +1,62 @@
+package util
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"google.golang.org/protobuf/encoding/protojson"
+	"jochum.dev/jo-micro/router/internal/proto/routerclientpb"
+)
+
+func TestProtoSchema(t *testing.T) {
+	schema, err := ProtoSchema(&routerclientpb.RoutesReply{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compiled, err := jsonschema.CompileString("schema.json", schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := &routerclientpb.RoutesReply{Routes: []*routerclientpb.RoutesReply_Route{{
+		Path:              "/users/:id",
+		AuthRequired:      true,
+		RatelimitClientIP: []string{"10-S"},
+		MaxBodySize:       1024,
+	}}}
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		doc   string
+		valid bool
+	}{
+		{name: "jsonpb", doc: string(data), valid: true},
+		{name: "quoted int64", doc: `{"routes":[{"maxBodySize":"1024"}]}`, valid: true},
+		{name: "unknown field", doc: `{"routes":[{"nope":1}]}`},
+		{name: "wrong type", doc: `{"routes":[{"authRequired":"yes"}]}`},
+		{name: "not an integer", doc: `{"routes":[{"maxBodySize":"1k"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc interface{}
+			dec := json.NewDecoder(strings.NewReader(tt.doc))
+			dec.UseNumber()
+			if err := dec.Decode(&doc); err != nil {
+				t.Fatal(err)
+			}
+
+			err := compiled.Validate(doc)
+			if (err == nil) != tt.valid {
+				t.Errorf("got %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
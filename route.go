@@ -2,18 +2,133 @@ package router
 
 import (
 	"log"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"jochum.dev/jo-micro/router/internal/util"
+)
+
+const (
+	TypeRPC         = "rpc"         // call Endpoint with the go-micro client
+	TypeHTTP        = "http"        // reverse proxy to a plain HTTP upstream
+	TypeRedirect    = "redirect"    // redirect to Redirect
+	TypeMaintenance = "maintenance" // answer with 503 and the maintenance body of microrouterd
+)
+
+const (
+	RatelimitKeyClientIP = "ip"     // the client IP
+	RatelimitKeyUser     = "user"   // the ID of the authenticated user
+	RatelimitKeyAPIKey   = "apikey" // the X-API-Key header, it's not validated so pair it with a client IP limit
+)
+
+// RatelimitKeyHeader counts on the value of the header name, clients pick it so pair it with a client IP limit
+func RatelimitKeyHeader(name string) string {
+	return "header:" + name
+}
+
+// RatelimitKeyParam counts on the value of the route param name, clients pick it so pair it with a client IP limit
+func RatelimitKeyParam(name string) string {
+	return "param:" + name
+}
+
+// RatelimitKeyClaim counts on the value of the claim name of the authenticated user
+func RatelimitKeyClaim(name string) string {
+	return "claim:" + name
+}
+
+const (
+	ParamTypeString = "string"
+	ParamTypeInt    = "int"
+	ParamTypeFloat  = "float"
+	ParamTypeBool   = "bool"
+	ParamTypeArray  = "array" // all values of a query param or header, a comma separated path param
 )
 
+const (
+	ArrayStyleRepeat   = "repeat"   // ?tag=a&tag=b
+	ArrayStyleComma    = "comma"    // ?tag=a,b
+	ArrayStyleBrackets = "brackets" // ?tag[]=a&tag[]=b
+)
+
+const (
+	ParamLocationPath   = "path"
+	ParamLocationQuery  = "query"
+	ParamLocationHeader = "header"
+)
+
+// Param is a param with a type, Location empty means the path or the query like Params,
+// Default gets used when the request has no value, Items is the type of the values of an array
+type Param struct {
+	Name     string
+	Type     string
+	Location string
+	Required bool
+	Default  string
+	Items    string
+}
+
+// Ratelimit limits requests with the same Key to Rates, routes with the same Group share the limit.
+// Keys that aren't in the request fall back to the client IP, claims to the user.
+type Ratelimit struct {
+	Key   string
+	Rates []string
+	Group string
+}
+
+// RewriteRule rewrites requests matching Match to Replace or to the route if Replace is empty,
+// Match is a regexp when it starts with "^" else a template like "/api/v2/users/:userId"
+type RewriteRule struct {
+	Match   string
+	Replace string
+}
+
 type Route struct {
 	IsGlobal     bool // isGlobal=True == no prefix route
+	Type         string
 	Method       string
 	Path         string
 	Endpoint     interface{}
 	Params       []string
-	AuthRequired bool // Default false
-	// https://github.com/ulule/limiter - default is no rate Limiter at all, put the strictes limit first
+	TypedParams  []Param
+	ArrayStyle   string // how array query params are sent, ArrayStyleRepeat if empty
+	AuthRequired bool   // Default false
+	// https://github.com/ulule/limiter - default is no rate Limiter at all, put the strictes limit first,
+	// append ":sliding" for a sliding window or ":token:<burst>" for a token bucket, e.g. "10-S:token:20"
 	RatelimitClientIP []string
 	RatelimitUser     []string
+	Ratelimits        []Ratelimit
+	// Upstream is the base URL for TypeHTTP, empty means the address of a node of the service from the registry
+	Upstream string
+	// UpstreamPath is the path for TypeHTTP, ":name" and "*name" get replaced by params, empty means the request path without the prefix
+	UpstreamPath string
+	// GRPCWeb accepts gRPC-Web and gRPC requests besides JSON, the endpoint gets called with the protobuf codec
+	GRPCWeb bool
+	// Rewrites are matched against the full request path before routing, and only for requests with Method
+	Rewrites []RewriteRule
+	// Redirect is the target for TypeRedirect, ":name" and "*name" get replaced by params
+	Redirect string
+	// RedirectCode is the HTTP status for TypeRedirect, http.StatusFound if 0
+	RedirectCode int
+	// MaxInFlight limits the concurrent requests, 0 means no limit
+	MaxInFlight int
+	// QueueSize requests wait up to QueueTimeout for a free slot when MaxInFlight is reached, else they get a 503,
+	// without a QueueTimeout they wait as long as the router's default
+	QueueSize    int
+	QueueTimeout time.Duration
+	// AllowCIDRs are the only client networks allowed, DenyCIDRs get a 403 even if they are allowed
+	AllowCIDRs []string
+	DenyCIDRs  []string
+	// MaxBodySize, MaxMultipartParts, MaxMultipartPartSize and MaxJSONDepth override the limits of microrouterd, 0 means its limit
+	MaxBodySize          int64
+	MaxMultipartParts    int
+	MaxMultipartPartSize int64
+	MaxJSONDepth         int
+	// Schema is a JSON Schema microrouterd validates the request (params and body) against before it calls Endpoint
+	Schema string
+	// Audit publishes an audit event for every request to the audit topic of microrouterd
+	Audit bool
+	// ForwardCredentials passes the Authorization and Cookie headers of the client to the upstream of TypeHTTP
+	ForwardCredentials bool
 }
 
 type Option func(*Route)
@@ -21,20 +136,40 @@ type Option func(*Route)
 func NewRoute(opts ...Option) *Route {
 	route := &Route{
 		IsGlobal:          false,
+		Type:              TypeRPC,
 		Method:            MethodGet,
 		Path:              "/",
 		Endpoint:          nil,
 		Params:            []string{},
+		TypedParams:       []Param{},
+		ArrayStyle:        ArrayStyleRepeat,
 		AuthRequired:      false,
 		RatelimitClientIP: []string{},
 		RatelimitUser:     []string{},
+		Ratelimits:        []Ratelimit{},
+		Upstream:          "",
+		UpstreamPath:      "",
+		GRPCWeb:           false,
+		Rewrites:          []RewriteRule{},
+		Redirect:          "",
+		RedirectCode:      0,
+		MaxInFlight:       0,
+		QueueSize:         0,
+		QueueTimeout:      0,
+		AllowCIDRs:        []string{},
+		DenyCIDRs:         []string{},
+		MaxBodySize:       0,
+		MaxMultipartParts: 0,
+		MaxJSONDepth:      0,
+		Schema:            "",
+		Audit:             false,
 	}
 
 	for _, o := range opts {
 		o(route)
 	}
 
-	if route.Endpoint == nil {
+	if route.Type == TypeRPC && route.Endpoint == nil {
 		log.Println("router.Endpoint() is a required argument")
 		return nil
 	}
@@ -48,6 +183,12 @@ func IsGlobal(n bool) Option {
 	}
 }
 
+func Type(n string) Option {
+	return func(o *Route) {
+		o.Type = n
+	}
+}
+
 func Method(n string) Option {
 	return func(o *Route) {
 		o.Method = n
@@ -72,6 +213,18 @@ func Params(n ...string) Option {
 	}
 }
 
+func TypedParams(n ...Param) Option {
+	return func(o *Route) {
+		o.TypedParams = n
+	}
+}
+
+func ArrayStyle(n string) Option {
+	return func(o *Route) {
+		o.ArrayStyle = n
+	}
+}
+
 func AuthRequired() Option {
 	return func(o *Route) {
 		o.AuthRequired = true
@@ -89,3 +242,116 @@ func RatelimitUser(n ...string) Option {
 		o.RatelimitUser = n
 	}
 }
+
+func RatelimitKey(key string, rates ...string) Option {
+	return func(o *Route) {
+		o.Ratelimits = append(o.Ratelimits, Ratelimit{Key: key, Rates: rates})
+	}
+}
+
+func RatelimitGroup(group, key string, rates ...string) Option {
+	return func(o *Route) {
+		o.Ratelimits = append(o.Ratelimits, Ratelimit{Key: key, Rates: rates, Group: group})
+	}
+}
+
+func Upstream(n string) Option {
+	return func(o *Route) {
+		o.Upstream = n
+	}
+}
+
+func UpstreamPath(n string) Option {
+	return func(o *Route) {
+		o.UpstreamPath = n
+	}
+}
+
+func GRPCWeb() Option {
+	return func(o *Route) {
+		o.GRPCWeb = true
+	}
+}
+
+func Rewrite(match, replace string) Option {
+	return func(o *Route) {
+		o.Rewrites = append(o.Rewrites, RewriteRule{Match: match, Replace: replace})
+	}
+}
+
+func Redirect(n string, code int) Option {
+	return func(o *Route) {
+		o.Type = TypeRedirect
+		o.Redirect = n
+		o.RedirectCode = code
+	}
+}
+
+func Concurrency(maxInFlight, queueSize int, queueTimeout time.Duration) Option {
+	return func(o *Route) {
+		o.MaxInFlight = maxInFlight
+		o.QueueSize = queueSize
+		o.QueueTimeout = queueTimeout
+	}
+}
+
+func AllowCIDRs(n ...string) Option {
+	return func(o *Route) {
+		o.AllowCIDRs = n
+	}
+}
+
+func DenyCIDRs(n ...string) Option {
+	return func(o *Route) {
+		o.DenyCIDRs = n
+	}
+}
+
+func MaxBodySize(n int64) Option {
+	return func(o *Route) {
+		o.MaxBodySize = n
+	}
+}
+
+func MaxMultipart(parts int, partSize int64) Option {
+	return func(o *Route) {
+		o.MaxMultipartParts = parts
+		o.MaxMultipartPartSize = partSize
+	}
+}
+
+func MaxJSONDepth(n int) Option {
+	return func(o *Route) {
+		o.MaxJSONDepth = n
+	}
+}
+
+func Schema(n string) Option {
+	return func(o *Route) {
+		o.Schema = n
+	}
+}
+
+func Audit() Option {
+	return func(o *Route) {
+		o.Audit = true
+	}
+}
+
+func ForwardCredentials() Option {
+	return func(o *Route) {
+		o.ForwardCredentials = true
+	}
+}
+
+// SchemaFromMessage generates the Schema from the request message of Endpoint
+func SchemaFromMessage(msg proto.Message) Option {
+	return func(o *Route) {
+		schema, err := util.ProtoSchema(msg)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		o.Schema = schema
+	}
+}